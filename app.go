@@ -18,6 +18,7 @@ import (
 	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/cosmos/cosmos-sdk/x/auth/genaccounts"
 	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/cosmos-sdk/x/crisis"
 	distr "github.com/cosmos/cosmos-sdk/x/distribution"
 	"github.com/cosmos/cosmos-sdk/x/genutil"
 	"github.com/cosmos/cosmos-sdk/x/params"
@@ -40,6 +41,7 @@ var (
 		genutil.AppModuleBasic{},
 		auth.AppModuleBasic{},
 		bank.AppModuleBasic{},
+		crisis.AppModuleBasic{},
 		params.AppModuleBasic{},
 		multisig.AppModule{},
 		staking.AppModuleBasic{},
@@ -80,6 +82,7 @@ type multisigApp struct {
 	stakingKeeper       staking.Keeper
 	slashingKeeper      slashing.Keeper
 	distrKeeper         distr.Keeper
+	crisisKeeper        crisis.Keeper
 	feeCollectionKeeper auth.FeeCollectionKeeper
 	paramsKeeper        params.Keeper
 	nsKeeper            multisig.Keeper
@@ -89,7 +92,7 @@ type multisigApp struct {
 }
 
 // NewMultiSigApp is a constructor function for multisigApp
-func NewMultiSigApp(logger log.Logger, db dbm.DB) *multisigApp {
+func NewMultiSigApp(logger log.Logger, db dbm.DB, invCheckPeriod uint) *multisigApp {
 
 	// First define the top level codec that will be shared by the different modules
 	cdc := MakeCodec()
@@ -123,6 +126,8 @@ func NewMultiSigApp(logger log.Logger, db dbm.DB) *multisigApp {
 	stakingSubspace := app.paramsKeeper.Subspace(staking.DefaultParamspace)
 	distrSubspace := app.paramsKeeper.Subspace(distr.DefaultParamspace)
 	slashingSubspace := app.paramsKeeper.Subspace(slashing.DefaultParamspace)
+	crisisSubspace := app.paramsKeeper.Subspace(crisis.DefaultParamspace)
+	multisigSubspace := app.paramsKeeper.Subspace(multisig.DefaultParamspace)
 
 	// The AccountKeeper handles address -> account lookups
 	app.accountKeeper = auth.NewAccountKeeper(
@@ -162,6 +167,16 @@ func NewMultiSigApp(logger log.Logger, db dbm.DB) *multisigApp {
 		distr.DefaultCodespace,
 	)
 
+	// The CrisisKeeper checks the invariants of the modules every invCheckPeriod
+	// blocks, and halts the chain when one is reported broken
+	app.crisisKeeper = crisis.NewKeeper(
+		crisisSubspace,
+		invCheckPeriod,
+		app.distrKeeper,
+		app.bankKeeper,
+		app.feeCollectionKeeper,
+	)
+
 	app.slashingKeeper = slashing.NewKeeper(
 		app.cdc,
 		app.keySlashing,
@@ -182,11 +197,14 @@ func NewMultiSigApp(logger log.Logger, db dbm.DB) *multisigApp {
 	// It handles interactions with the msig store
 	app.nsKeeper = multisig.NewKeeper(
 		app.bankKeeper,
+		app.accountKeeper,
 		app.keyNS,
+		multisigSubspace,
 		app.cdc,
+		multisig.DefaultCodespace,
 	)
 
-	app.mm = module.NewManager(
+	modules := []module.AppModule{
 		genaccounts.NewAppModule(app.accountKeeper),
 		genutil.NewAppModule(app.accountKeeper, app.stakingKeeper, app.BaseApp.DeliverTx),
 		auth.NewAppModule(app.accountKeeper, app.feeCollectionKeeper),
@@ -195,10 +213,16 @@ func NewMultiSigApp(logger log.Logger, db dbm.DB) *multisigApp {
 		distr.NewAppModule(app.distrKeeper),
 		slashing.NewAppModule(app.slashingKeeper, app.stakingKeeper),
 		staking.NewAppModule(app.stakingKeeper, app.feeCollectionKeeper, app.distrKeeper, app.accountKeeper),
-	)
+	}
+	// the crisis module holds a copy of its keeper, so the invariants of the
+	// other modules are registered before it is created
+	for _, m := range modules {
+		m.RegisterInvariants(&app.crisisKeeper)
+	}
+	app.mm = module.NewManager(append(modules, crisis.NewAppModule(app.crisisKeeper, logger))...)
 
-	app.mm.SetOrderBeginBlockers(distr.ModuleName, slashing.ModuleName)
-	app.mm.SetOrderEndBlockers(staking.ModuleName)
+	app.mm.SetOrderBeginBlockers(multisig.ModuleName, distr.ModuleName, slashing.ModuleName)
+	app.mm.SetOrderEndBlockers(crisis.ModuleName, staking.ModuleName, multisig.ModuleName)
 
 	// Sets the order of Genesis - Order matters, genutil is to always come last
 	app.mm.SetOrderInitGenesis(
@@ -209,6 +233,7 @@ func NewMultiSigApp(logger log.Logger, db dbm.DB) *multisigApp {
 		bank.ModuleName,
 		slashing.ModuleName,
 		multisig.ModuleName,
+		crisis.ModuleName,
 		genutil.ModuleName,
 	)
 
@@ -270,8 +295,22 @@ func (app *multisigApp) InitChainer(ctx sdk.Context, req abci.RequestInitChain)
 }
 
 func (app *multisigApp) BeginBlocker(ctx sdk.Context, req abci.RequestBeginBlock) abci.ResponseBeginBlock {
+	app.upgrade(ctx)
 	return app.mm.BeginBlock(ctx, req)
 }
+
+// upgrade sets the state of the modules added since the store was written,
+// when it is missing in the block processed. Each module state is checked on
+// its own, as it does not depend on the multisig schema version. The multisig
+// store itself is migrated by the multisig BeginBlocker.
+func (app *multisigApp) upgrade(ctx sdk.Context) {
+	// chains started before the crisis module was added have no constant fee,
+	// which is read when an invariant is verified
+	crisisSubspace, _ := app.paramsKeeper.GetSubspace(crisis.DefaultParamspace)
+	if !crisisSubspace.Has(ctx, crisis.ParamStoreKeyConstantFee) {
+		app.crisisKeeper.SetConstantFee(ctx, crisis.DefaultGenesisState().ConstantFee)
+	}
+}
 func (app *multisigApp) EndBlocker(ctx sdk.Context, req abci.RequestEndBlock) abci.ResponseEndBlock {
 	return app.mm.EndBlock(ctx, req)
 }
@@ -287,6 +326,10 @@ func (app *multisigApp) ExportAppStateAndValidators(forZeroHeight bool, jailWhit
 	// as if they could withdraw from the start of the next block
 	ctx := app.NewContext(true, abci.Header{Height: app.LastBlockHeight()})
 
+	if forZeroHeight {
+		app.prepForZeroHeightGenesis(ctx)
+	}
+
 	genState := app.mm.ExportGenesis(ctx)
 	appState, err = codec.MarshalJSONIndent(app.cdc, genState)
 	if err != nil {
@@ -297,3 +340,8 @@ func (app *multisigApp) ExportAppStateAndValidators(forZeroHeight bool, jailWhit
 
 	return appState, validators, nil
 }
+
+// prepare for fresh start at zero height
+func (app *multisigApp) prepForZeroHeightGenesis(ctx sdk.Context) {
+	multisig.PrepForZeroHeightGenesis(ctx, app.nsKeeper)
+}
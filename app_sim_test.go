@@ -0,0 +1,90 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"testing"
+
+	multisigsim "github.com/cbarraford/cosmos-multisig/x/multisig/simulation"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/simulation"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+)
+
+var (
+	seed        int64
+	numBlocks   int
+	blockSize   int
+	verbose     bool
+	lean        bool
+	commit      bool
+	onOperation bool
+)
+
+func init() {
+	flag.Int64Var(&seed, "SimulationSeed", 42, "simulation random seed")
+	flag.IntVar(&numBlocks, "SimulationNumBlocks", 20, "number of blocks")
+	flag.IntVar(&blockSize, "SimulationBlockSize", 50, "operations per block")
+	flag.BoolVar(&verbose, "SimulationVerbose", false, "verbose log output")
+	flag.BoolVar(&lean, "SimulationLean", false, "lean simulation log output")
+	flag.BoolVar(&commit, "SimulationCommit", true, "have the simulation commit")
+	flag.BoolVar(&onOperation, "SimulateEveryOperation", false, "run invariants every operation")
+
+	config := sdk.GetConfig()
+	config.SetBech32PrefixForAccount("msig", "msigp")
+	config.SetBech32PrefixForValidator("msigv", "msigvp")
+	config.SetBech32PrefixForConsensusNode("msigc", "msigcp")
+}
+
+func newSimulationApp() *multisigApp {
+	logger := log.NewNopLogger()
+	if verbose {
+		logger = log.TestingLogger()
+	}
+	return NewMultiSigApp(logger, dbm.NewMemDB(), 0)
+}
+
+// runs a simulation of the app from the seed
+func simulate(t *testing.T, app *multisigApp) {
+	_, err := simulation.SimulateFromSeed(
+		t, os.Stdout, app.BaseApp, SimulationAppState, seed,
+		app.SimulationOperations(), app.SimulationInvariants(),
+		numBlocks, blockSize, commit, lean, onOperation,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFullAppSimulation(t *testing.T) {
+	simulate(t, newSimulationApp())
+}
+
+// TestAppImportExport exports the state of a simulated app, imports it in a
+// new app and checks the multisig stores of both apps are equal
+func TestAppImportExport(t *testing.T) {
+	app := newSimulationApp()
+	simulate(t, app)
+
+	appState, _, err := app.ExportAppStateAndValidators(false, []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var genesisState GenesisState
+	app.cdc.MustUnmarshalJSON(appState, &genesisState)
+
+	newApp := newSimulationApp()
+	ctxB := newApp.NewContext(true, abci.Header{Height: app.LastBlockHeight()})
+	newApp.mm.InitGenesis(ctxB, genesisState)
+
+	ctxA := app.NewContext(true, abci.Header{Height: app.LastBlockHeight()})
+	kvA, kvB, count, equal := sdk.DiffKVStores(ctxA.KVStore(app.keyNS), ctxB.KVStore(newApp.keyNS), nil)
+	fmt.Printf("compared %d key/value pairs of the multisig store\n", count)
+	if !equal {
+		t.Fatalf("unequal multisig stores at key %X:\n%s", kvA.Key, multisigsim.DecodeStore(app.cdc, kvA, kvB))
+	}
+}
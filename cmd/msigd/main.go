@@ -21,6 +21,11 @@ import (
 	tmtypes "github.com/tendermint/tendermint/types"
 )
 
+// flag to assert the registered invariants every N blocks
+const flagInvCheckPeriod = "inv-check-period"
+
+var invCheckPeriod uint
+
 func main() {
 	cobra.EnableCommandSorting = false
 
@@ -50,6 +55,8 @@ func main() {
 	)
 
 	server.AddCommands(ctx, cdc, rootCmd, newApp, exportAppStateAndTMValidators)
+	rootCmd.PersistentFlags().UintVar(&invCheckPeriod, flagInvCheckPeriod,
+		0, "Assert registered invariants every N blocks")
 
 	// prepare and add flags
 	executor := cli.PrepareBaseCmd(rootCmd, "NS", app.DefaultNodeHome)
@@ -60,7 +67,7 @@ func main() {
 }
 
 func newApp(logger log.Logger, db dbm.DB, traceStore io.Writer) abci.Application {
-	return app.NewMultiSigApp(logger, db)
+	return app.NewMultiSigApp(logger, db, invCheckPeriod)
 }
 
 func exportAppStateAndTMValidators(
@@ -68,7 +75,7 @@ func exportAppStateAndTMValidators(
 ) (json.RawMessage, []tmtypes.GenesisValidator, error) {
 
 	if height != -1 {
-		nsApp := app.NewMultiSigApp(logger, db)
+		nsApp := app.NewMultiSigApp(logger, db, uint(1))
 		err := nsApp.LoadHeight(height)
 		if err != nil {
 			return nil, nil, err
@@ -76,7 +83,7 @@ func exportAppStateAndTMValidators(
 		return nsApp.ExportAppStateAndValidators(forZeroHeight, jailWhiteList)
 	}
 
-	nsApp := app.NewMultiSigApp(logger, db)
+	nsApp := app.NewMultiSigApp(logger, db, uint(1))
 
 	return nsApp.ExportAppStateAndValidators(forZeroHeight, jailWhiteList)
 }
@@ -0,0 +1,121 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/cbarraford/cosmos-multisig/x/multisig"
+	v1 "github.com/cbarraford/cosmos-multisig/x/multisig/legacy/v1"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+)
+
+// TestMigrateV1ToV2 seeds a store written by the first release, migrates it
+// and checks the records, indexes and params of the version 2 store
+func TestMigrateV1ToV2(t *testing.T) {
+	app := newSimulationApp()
+	ctx := app.NewContext(true, abci.Header{Height: 10})
+	app.accountKeeper.SetParams(ctx, auth.DefaultParams())
+
+	pubkeys := make([]string, 3)
+	for i := range pubkeys {
+		pubkeys[i] = sdk.MustBech32ifyAccPub(secp256k1.GenPrivKey().PubKey())
+	}
+	wallet, err := multisig.NewMultiSigWallet("migrated", pubkeys, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacyWallet := v1.MultiSigWallet{
+		Name:     wallet.Name,
+		MinSigTx: wallet.MinSigTx,
+		Address:  wallet.Address,
+		PubKeys:  wallet.PubKeys,
+	}
+	acc := app.accountKeeper.NewAccountWithAddress(ctx, wallet.Address)
+	if err := acc.SetSequence(3); err != nil {
+		t.Fatal(err)
+	}
+	app.accountKeeper.SetAccount(ctx, acc)
+
+	// signatures of the first release, the given ones signed
+	signatures := func(signed int) []v1.Signature {
+		sigs := make([]v1.Signature, len(pubkeys))
+		for i, pubkey := range pubkeys {
+			sigs[i].PubKey = pubkey
+			if i < signed {
+				sigs[i].Signature = "signature"
+			}
+		}
+		return sigs
+	}
+	recipient := sdk.AccAddress(secp256k1.GenPrivKey().PubKey().Address())
+	coins := sdk.NewCoins(sdk.NewInt64Coin(sdk.DefaultBondDenom, 10))
+	legacyTransactions := []v1.Transaction{
+		{UUID: "pending", From: wallet.Address, To: recipient, Coins: coins, Signatures: signatures(1), CreatedAt: 1},
+		{UUID: "ready", From: wallet.Address, To: recipient, Coins: coins, Signatures: signatures(2), CreatedAt: 2},
+		{UUID: "completed", From: wallet.Address, To: recipient, Coins: coins, Signatures: signatures(2), TxID: "ABCD", CreatedAt: 3},
+	}
+
+	store := ctx.KVStore(app.keyNS)
+	store.Set([]byte(v1.WalletKeyPrefix+wallet.Address.String()), app.cdc.MustMarshalBinaryBare(legacyWallet))
+	for _, transaction := range legacyTransactions {
+		store.Set([]byte(v1.TransactionKeyPrefix+transaction.UUID), app.cdc.MustMarshalBinaryBare(transaction))
+	}
+
+	if version := app.nsKeeper.GetSchemaVersion(ctx); version != v1.SchemaVersion {
+		t.Fatalf("seeded store has schema version %d", version)
+	}
+	if err := multisig.Migrate(ctx, app.nsKeeper); err != nil {
+		t.Fatal(err)
+	}
+	if version := app.nsKeeper.GetSchemaVersion(ctx); version != multisig.SchemaVersion {
+		t.Fatalf("migrated store has schema version %d", version)
+	}
+
+	// no record is left under a string key
+	iterator := store.Iterator([]byte{' '}, nil)
+	if iterator.Valid() {
+		t.Errorf("version 1 key %q is left", iterator.Key())
+	}
+	iterator.Close()
+
+	migrated := app.nsKeeper.GetWallet(ctx, wallet.Address.String())
+	if migrated.Name != wallet.Name || migrated.MinSigTx != wallet.MinSigTx ||
+		!migrated.Address.Equals(wallet.Address) || len(migrated.PubKeys) != len(pubkeys) ||
+		!migrated.Successor.Empty() {
+		t.Errorf("wallet migrated to %v", migrated)
+	}
+
+	statuses := map[string]multisig.TransactionStatus{
+		"pending":   multisig.StatusPending,
+		"ready":     multisig.StatusReadyToBroadcast,
+		"completed": multisig.StatusCompleted,
+	}
+	for uid, status := range statuses {
+		transaction := app.nsKeeper.GetTransaction(ctx, uid)
+		if transaction.Status != status {
+			t.Errorf("transaction %s migrated with status %s, expected %s", uid, transaction.Status, status)
+		}
+		if transaction.Sequence != 3 || transaction.AccountNumber != acc.GetAccountNumber() {
+			t.Errorf("transaction %s pinned to account %d sequence %d", uid, transaction.AccountNumber, transaction.Sequence)
+		}
+	}
+	if count := app.nsKeeper.CountPendingTransactions(ctx, wallet.Address); count != 2 {
+		t.Errorf("wallet has %d open transactions, expected 2", count)
+	}
+
+	if params := app.nsKeeper.GetParams(ctx); params.String() != multisig.DefaultParams().String() {
+		t.Errorf("migrated params are %s", params)
+	}
+	for _, invar := range []sdk.Invariant{
+		multisig.RegisteredWalletsInvariant(app.nsKeeper),
+		multisig.SignatureSlotsInvariant(app.nsKeeper),
+		multisig.IndexesInvariant(app.nsKeeper),
+	} {
+		if err := invar(ctx); err != nil {
+			t.Error(err)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"encoding/json"
+	"math/rand"
+	"time"
+
+	multisigsim "github.com/cbarraford/cosmos-multisig/x/multisig/simulation"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/auth/genaccounts"
+	"github.com/cosmos/cosmos-sdk/x/simulation"
+	"github.com/cosmos/cosmos-sdk/x/staking"
+)
+
+// SimulationAppState generates the genesis state of an app simulation: every
+// account holds the same random amount of stake, the first accounts are
+// bonded validators, and the multisig genesis is randomized
+func SimulationAppState(r *rand.Rand, accs []simulation.Account, genesisTimestamp time.Time) (json.RawMessage, []simulation.Account, string) {
+	cdc := MakeCodec()
+	genesisState := NewDefaultGenesisState()
+
+	amount := int64(r.Intn(1e12))
+	numAccs := int64(len(accs))
+	numInitiallyBonded := int64(simulation.RandIntBetween(r, 1, 250))
+	if numInitiallyBonded > numAccs {
+		numInitiallyBonded = numAccs
+	}
+
+	var genesisAccounts []genaccounts.GenesisAccount
+	for _, acc := range accs {
+		bacc := auth.NewBaseAccountWithAddress(acc.Address)
+		bacc.SetCoins(sdk.NewCoins(sdk.NewCoin(sdk.DefaultBondDenom, sdk.NewInt(amount))))
+		genesisAccounts = append(genesisAccounts, genaccounts.NewGenesisAccount(&bacc))
+	}
+	genesisState[genaccounts.ModuleName] = cdc.MustMarshalJSON(genesisAccounts)
+
+	stakingGenesis := staking.DefaultGenesisState()
+	for i := 0; i < int(numInitiallyBonded); i++ {
+		valAddr := sdk.ValAddress(accs[i].Address)
+		validator := staking.NewValidator(valAddr, accs[i].PubKey, staking.Description{})
+		validator.Tokens = sdk.NewInt(amount)
+		validator.DelegatorShares = sdk.NewDec(amount)
+		stakingGenesis.Validators = append(stakingGenesis.Validators, validator)
+		stakingGenesis.Delegations = append(stakingGenesis.Delegations,
+			staking.NewDelegation(accs[i].Address, valAddr, sdk.NewDec(amount)))
+	}
+	// the pool holds the coins of the accounts and the validator tokens
+	stakingGenesis.Pool.NotBondedTokens = sdk.NewInt((amount * numAccs) + (numInitiallyBonded * amount))
+	genesisState[staking.ModuleName] = cdc.MustMarshalJSON(stakingGenesis)
+
+	multisigsim.RandomizedGenState(cdc, r, accs, genesisState)
+
+	return cdc.MustMarshalJSON(genesisState), accs, "simulation"
+}
+
+// SimulationOperations returns the weighted operations of an app simulation
+func (app *multisigApp) SimulationOperations() []simulation.WeightedOperation {
+	return []simulation.WeightedOperation{
+		{Weight: 5, Op: multisigsim.SimulateMsgCreateWallet(app.nsKeeper)},
+		{Weight: 50, Op: multisigsim.SimulateMsgCreateTransaction(app.bankKeeper, app.nsKeeper)},
+		{Weight: 100, Op: multisigsim.SimulateMsgSignTransaction(app.nsKeeper)},
+		{Weight: 50, Op: multisigsim.SimulateMsgCompleteTransaction(app.cdc, app.nsKeeper)},
+	}
+}
+
+// SimulationInvariants returns the invariants checked by an app simulation,
+// which are the invariants registered with the crisis module
+func (app *multisigApp) SimulationInvariants() []sdk.Invariant {
+	return app.crisisKeeper.Invariants()
+}
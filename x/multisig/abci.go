@@ -0,0 +1,21 @@
+package multisig
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// BeginBlocker migrates a store written by a previous version of the module,
+// in the first block processed after an upgrade
+func BeginBlocker(ctx sdk.Context, k Keeper) {
+	if k.GetSchemaVersion(ctx) == SchemaVersion {
+		return
+	}
+	if err := Migrate(ctx, k); err != nil {
+		panic(err)
+	}
+}
+
+// EndBlocker expires the transaction requests that reached their maximum age
+func EndBlocker(ctx sdk.Context, k Keeper) sdk.Tags {
+	return k.CleanupTransactions(ctx, k.TransactionLifetime(ctx))
+}
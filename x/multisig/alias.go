@@ -8,27 +8,151 @@ const (
 	ModuleName = types.ModuleName
 	RouterKey  = types.RouterKey
 	StoreKey   = types.StoreKey
+
+	SchemaVersion = types.SchemaVersion
+
+	DefaultCodespace              = types.DefaultCodespace
+	DefaultParamspace             = types.DefaultParamspace
+	DefaultMaxPubKeys             = types.DefaultMaxPubKeys
+	DefaultMaxPendingTransactions = types.DefaultMaxPendingTransactions
+	DefaultTransactionLifetime    = types.DefaultTransactionLifetime
+	DefaultGas                    = types.DefaultGas
+
+	SortAsc                = types.SortAsc
+	SortDesc               = types.SortDesc
+	StatusPending          = types.StatusPending
+	StatusReadyToBroadcast = types.StatusReadyToBroadcast
+	StatusBroadcast        = types.StatusBroadcast
+	StatusCompleted        = types.StatusCompleted
+	StatusCancelled        = types.StatusCancelled
+	StatusExpired          = types.StatusExpired
+	StatusRejected         = types.StatusRejected
+
+	CodeWalletExists        = types.CodeWalletExists
+	CodeWalletNotFound      = types.CodeWalletNotFound
+	CodeInvalidWallet       = types.CodeInvalidWallet
+	CodeWalletReplaced      = types.CodeWalletReplaced
+	CodeInvalidWalletUpdate = types.CodeInvalidWalletUpdate
+	CodeTxExists            = types.CodeTxExists
+	CodeTxNotFound          = types.CodeTxNotFound
+	CodeInvalidTx           = types.CodeInvalidTx
+	CodeNotMember           = types.CodeNotMember
+	CodeInvalidSigner       = types.CodeInvalidSigner
+	CodeInvalidSignature    = types.CodeInvalidSignature
+	CodeThresholdNotMet     = types.CodeThresholdNotMet
+	CodeAlreadyCompleted    = types.CodeAlreadyCompleted
+	CodeInvalidStatus       = types.CodeInvalidStatus
+	CodeConflictingVote     = types.CodeConflictingVote
+	CodeTooManyPending      = types.CodeTooManyPending
+	CodeDenomNotAllowed     = types.CodeDenomNotAllowed
+	CodeSequenceNotUsed     = types.CodeSequenceNotUsed
 )
 
 var (
-	NewMsgCreateWallet        = types.NewMsgCreateWallet
-	NewMultiSigWallet         = types.NewMultiSigWallet
-	NewMsgCreateTransaction   = types.NewMsgCreateTransaction
-	NewMsgSignTransaction     = types.NewMsgSignTransaction
-	NewMsgCompleteTransaction = types.NewMsgCompleteTransaction
-	NewTransaction            = types.NewTransaction
+	NewMsgCreateWallet               = types.NewMsgCreateWallet
+	NewMultiSigWallet                = types.NewMultiSigWallet
+	NewMsgCreateTransaction          = types.NewMsgCreateTransaction
+	NewMsgCreateMultiSendTransaction = types.NewMsgCreateMultiSendTransaction
+	NewMsgCreateMsgsTransaction      = types.NewMsgCreateMsgsTransaction
+	NewMsgSignTransaction            = types.NewMsgSignTransaction
+	NewMsgCompleteTransaction        = types.NewMsgCompleteTransaction
+	NewMsgCancelTransaction          = types.NewMsgCancelTransaction
+	NewMsgRejectTransaction          = types.NewMsgRejectTransaction
+	NewMsgProposeWalletUpdate        = types.NewMsgProposeWalletUpdate
+	NewWalletUpdate                  = types.NewWalletUpdate
+	NewTransaction                   = types.NewTransaction
+	NewMultiSendTransaction          = types.NewMultiSendTransaction
+	NewMsgsTransaction               = types.NewMsgsTransaction
+	ValidateWalletMsgs               = types.ValidateWalletMsgs
+	SumOutputs                       = types.SumOutputs
+	SumMsgsCoins                     = types.SumMsgsCoins
+	TransactionStatusFromString      = types.TransactionStatusFromString
+	PubKeyAddress                    = types.PubKeyAddress
+	EncodePubKey                     = types.EncodePubKey
+	NewParams                        = types.NewParams
+	DefaultParams                    = types.DefaultParams
+	ParamKeyTable                    = types.ParamKeyTable
+
+	KeyMaxPubKeys             = types.KeyMaxPubKeys
+	KeyMaxPendingTransactions = types.KeyMaxPendingTransactions
+	KeyTransactionLifetime    = types.KeyTransactionLifetime
+	KeyAllowedDenoms          = types.KeyAllowedDenoms
 	ModuleCdc                 = types.ModuleCdc
+	RequestableRoutes         = types.RequestableRoutes
 	RegisterCodec             = types.RegisterCodec
+
+	SchemaVersionKey               = types.SchemaVersionKey
+	WalletKeyPrefix                = types.WalletKeyPrefix
+	TransactionKeyPrefix           = types.TransactionKeyPrefix
+	TransactionCountKeyPrefix      = types.TransactionCountKeyPrefix
+	WalletUpdateKeyPrefix          = types.WalletUpdateKeyPrefix
+	WalletPubKeyKeyPrefix          = types.WalletPubKeyKeyPrefix
+	WalletTransactionKeyPrefix     = types.WalletTransactionKeyPrefix
+	RecipientTxKeyPrefix           = types.RecipientTxKeyPrefix
+	ExpiryQueueKeyPrefix           = types.ExpiryQueueKeyPrefix
+	OpenTransactionKeyPrefix       = types.OpenTransactionKeyPrefix
+	IndexKeyPrefixes               = types.IndexKeyPrefixes
+	WalletKey                      = types.WalletKey
+	TransactionKey                 = types.TransactionKey
+	TransactionCountKey            = types.TransactionCountKey
+	WalletUpdatesKey               = types.WalletUpdatesKey
+	WalletUpdateKey                = types.WalletUpdateKey
+	PubKeyWalletsKey               = types.PubKeyWalletsKey
+	WalletPubKeyKey                = types.WalletPubKeyKey
+	WalletTransactionsKey          = types.WalletTransactionsKey
+	WalletTransactionsHeightKey    = types.WalletTransactionsHeightKey
+	WalletTransactionKey           = types.WalletTransactionKey
+	RecipientTransactionsKey       = types.RecipientTransactionsKey
+	RecipientTransactionsHeightKey = types.RecipientTransactionsHeightKey
+	RecipientTransactionKey        = types.RecipientTransactionKey
+	ExpiryQueueHeightKey           = types.ExpiryQueueHeightKey
+	ExpiryQueueKey                 = types.ExpiryQueueKey
+	OpenTransactionsKey            = types.OpenTransactionsKey
+	OpenTransactionKey             = types.OpenTransactionKey
+	HeightBytes                    = types.HeightBytes
+
+	ErrWalletExists        = types.ErrWalletExists
+	ErrWalletNotFound      = types.ErrWalletNotFound
+	ErrInvalidWallet       = types.ErrInvalidWallet
+	ErrWalletReplaced      = types.ErrWalletReplaced
+	ErrInvalidWalletUpdate = types.ErrInvalidWalletUpdate
+	ErrTxExists            = types.ErrTxExists
+	ErrTxNotFound          = types.ErrTxNotFound
+	ErrInvalidTx           = types.ErrInvalidTx
+	ErrNotMember           = types.ErrNotMember
+	ErrInvalidSigner       = types.ErrInvalidSigner
+	ErrInvalidSignature    = types.ErrInvalidSignature
+	ErrThresholdNotMet     = types.ErrThresholdNotMet
+	ErrAlreadyCompleted    = types.ErrAlreadyCompleted
+	ErrInvalidStatus       = types.ErrInvalidStatus
+	ErrConflictingVote     = types.ErrConflictingVote
+	ErrTooManyPending      = types.ErrTooManyPending
+	ErrDenomNotAllowed     = types.ErrDenomNotAllowed
+	ErrSequenceNotUsed     = types.ErrSequenceNotUsed
 )
 
 type (
-	MsgCreateWallet        = types.MsgCreateWallet
-	MsgCreateTransaction   = types.MsgCreateTransaction
-	MsgSignTransaction     = types.MsgSignTransaction
-	MsgCompleteTransaction = types.MsgCompleteTransaction
-	QueryWallets           = types.QueryWallets
-	QueryTransactions      = types.QueryTransactions
-	Transaction            = types.Transaction
-	Signature              = types.Signature
-	MultiSigWallet         = types.MultiSigWallet
+	MsgCreateWallet               = types.MsgCreateWallet
+	MsgCreateTransaction          = types.MsgCreateTransaction
+	MsgCreateMultiSendTransaction = types.MsgCreateMultiSendTransaction
+	MsgCreateMsgsTransaction      = types.MsgCreateMsgsTransaction
+	MsgSignTransaction            = types.MsgSignTransaction
+	MsgCompleteTransaction        = types.MsgCompleteTransaction
+	MsgCancelTransaction          = types.MsgCancelTransaction
+	MsgRejectTransaction          = types.MsgRejectTransaction
+	MsgProposeWalletUpdate        = types.MsgProposeWalletUpdate
+	QueryWalletUpdates            = types.QueryWalletUpdates
+	QueryInvariants               = types.QueryInvariants
+	InvariantResult               = types.InvariantResult
+	WalletUpdate                  = types.WalletUpdate
+	QueryWallets                  = types.QueryWallets
+	QueryTransaction              = types.QueryTransaction
+	QueryTransactions             = types.QueryTransactions
+	QueryWalletsParams            = types.QueryWalletsParams
+	QueryTransactionsParams       = types.QueryTransactionsParams
+	Transaction                   = types.Transaction
+	Signature                     = types.Signature
+	TransactionStatus             = types.TransactionStatus
+	MultiSigWallet                = types.MultiSigWallet
+	Params                        = types.Params
 )
@@ -7,9 +7,21 @@ import (
 	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/client/context"
 	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/spf13/cobra"
 )
 
+const (
+	flagPage      = "page"
+	flagLimit     = "limit"
+	flagSort      = "sort"
+	flagStatus    = "status"
+	flagMinHeight = "min-height"
+	flagMaxHeight = "max-height"
+	flagRecipient = "recipient"
+	flagDenom     = "denom"
+)
+
 func GetQueryCmd(storeKey string, cdc *codec.Codec) *cobra.Command {
 	msigQueryCmd := &cobra.Command{
 		Use:                        types.ModuleName,
@@ -23,6 +35,11 @@ func GetQueryCmd(storeKey string, cdc *codec.Codec) *cobra.Command {
 		GetCmdWallets(storeKey, cdc),
 		GetCmdTransaction(storeKey, cdc),
 		GetCmdTransactions(storeKey, cdc),
+		GetCmdParams(storeKey, cdc),
+		GetCmdWalletUpdates(storeKey, cdc),
+		GetCmdUnsignedTx(storeKey, cdc),
+		GetCmdSignDoc(storeKey, cdc),
+		GetCmdCheckInvariants(storeKey, cdc),
 	)...)
 	return msigQueryCmd
 }
@@ -53,7 +70,7 @@ func GetCmdWallet(queryRoute string, cdc *codec.Codec) *cobra.Command {
 
 // GetCmdWallets queries a list of wallets contains a specific public key
 func GetCmdWallets(queryRoute string, cdc *codec.Codec) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "query-wallets [pub_key]",
 		Short: "Query for a list of wallets by public key",
 		Args:  cobra.ExactArgs(1),
@@ -62,7 +79,14 @@ func GetCmdWallets(queryRoute string, cdc *codec.Codec) *cobra.Command {
 
 			pubKey := args[0]
 
-			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/listWallets/%s", queryRoute, pubKey), nil)
+			page, _ := cmd.Flags().GetInt(flagPage)
+			limit, _ := cmd.Flags().GetInt(flagLimit)
+			params := types.NewQueryWalletsParams(page, limit)
+			if err := params.ValidateBasic(); err != nil {
+				return err
+			}
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/listWallets/%s", queryRoute, pubKey), cdc.MustMarshalJSON(params))
 			if err != nil {
 				fmt.Printf("could not get query wallets\n")
 				return nil
@@ -73,6 +97,9 @@ func GetCmdWallets(queryRoute string, cdc *codec.Codec) *cobra.Command {
 			return cliCtx.PrintOutput(out)
 		},
 	}
+	cmd.Flags().Int(flagPage, 1, "page of results to return")
+	cmd.Flags().Int(flagLimit, 0, "number of results per page (0 returns all results)")
+	return cmd
 }
 
 // GetCmdTransaction queries information about a domain
@@ -92,7 +119,7 @@ func GetCmdTransaction(queryRoute string, cdc *codec.Codec) *cobra.Command {
 				return nil
 			}
 
-			var out types.Transaction
+			var out types.QueryTransaction
 			cdc.MustUnmarshalJSON(res, &out)
 			return cliCtx.PrintOutput(out)
 		},
@@ -101,19 +128,40 @@ func GetCmdTransaction(queryRoute string, cdc *codec.Codec) *cobra.Command {
 
 // GetCmdTransactions queries a list of transaction for a specific wallet
 func GetCmdTransactions(queryRoute string, cdc *codec.Codec) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "query-transactions [wallet_address]",
 		Short: "Query for a list of transaction by wallet address",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
 			cliCtx := context.NewCLIContext().WithCodec(cdc)
 
 			addr := args[0]
 
-			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/listTransactions/%s", queryRoute, addr), nil)
+			page, _ := cmd.Flags().GetInt(flagPage)
+			limit, _ := cmd.Flags().GetInt(flagLimit)
+			sort, _ := cmd.Flags().GetString(flagSort)
+			status, _ := cmd.Flags().GetString(flagStatus)
+			minHeight, _ := cmd.Flags().GetInt64(flagMinHeight)
+			maxHeight, _ := cmd.Flags().GetInt64(flagMaxHeight)
+			denom, _ := cmd.Flags().GetString(flagDenom)
+
+			var recipient sdk.AccAddress
+			if to, _ := cmd.Flags().GetString(flagRecipient); to != "" {
+				recipient, err = sdk.AccAddressFromBech32(to)
+				if err != nil {
+					return err
+				}
+			}
+
+			params := types.NewQueryTransactionsParams(page, limit, sort, status, minHeight, maxHeight, recipient, denom)
+			if err = params.ValidateBasic(); err != nil {
+				return err
+			}
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/listTransactions/%s", queryRoute, addr), cdc.MustMarshalJSON(params))
 			if err != nil {
-				fmt.Printf("could not get query wallets\n")
-				return nil
+				return err
 			}
 
 			var out types.QueryTransactions
@@ -121,4 +169,131 @@ func GetCmdTransactions(queryRoute string, cdc *codec.Codec) *cobra.Command {
 			return cliCtx.PrintOutput(out)
 		},
 	}
+	cmd.Flags().Int(flagPage, 1, "page of results to return")
+	cmd.Flags().Int(flagLimit, 0, "number of results per page (0 returns all results)")
+	cmd.Flags().String(flagSort, types.SortAsc, "sort order by creation height (asc|desc)")
+	cmd.Flags().String(flagStatus, "", "only return transactions with this status (pending|ready_to_broadcast|broadcast|completed|cancelled|expired|rejected)")
+	cmd.Flags().Int64(flagMinHeight, 0, "only return transactions created at or after this height")
+	cmd.Flags().Int64(flagMaxHeight, 0, "only return transactions created at or before this height")
+	cmd.Flags().String(flagRecipient, "", "only return transactions sending to this address")
+	cmd.Flags().String(flagDenom, "", "only return transactions sending this denom")
+	return cmd
+}
+
+// GetCmdParams queries the parameters of the multisig module
+func GetCmdParams(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "params",
+		Short: "Query the current multisig parameters",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/params", queryRoute), nil)
+			if err != nil {
+				return err
+			}
+
+			var out types.Params
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+}
+
+// GetCmdWalletUpdates queries the pending update proposals of a wallet
+func GetCmdWalletUpdates(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "query-wallet-updates [wallet_address]",
+		Short: "Query for the pending update proposals of a wallet",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			addr := args[0]
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/listWalletUpdates/%s", queryRoute, addr), nil)
+			if err != nil {
+				return err
+			}
+
+			var out types.QueryWalletUpdates
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
+}
+
+// GetCmdUnsignedTx queries the unsigned multisig transaction of a transaction request
+func GetCmdUnsignedTx(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "unsigned-tx [uuid]",
+		Short: "Get the unsigned transaction of a transaction request",
+		Long: `Get the unsigned transaction of a transaction request, with its msgs, fee
+and memo. The output can be signed with "msigcli tx sign --multisig".`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			uid := args[0]
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/getUnsignedTx/%s", queryRoute, uid), nil)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+}
+
+// GetCmdSignDoc queries the sign doc of a transaction request
+func GetCmdSignDoc(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "sign-doc [uuid]",
+		Short: "Get the exact bytes wallet members sign for a transaction request",
+		Long: `Get the exact bytes wallet members sign for a transaction request: the
+sorted JSON of its StdSignDoc, with the chain-id and the current account number
+and sequence of the wallet. A stale pending request is pinned to them when it
+is signed. Requests which can no longer be signed have no sign doc.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			uid := args[0]
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/getSignDoc/%s", queryRoute, uid), nil)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println(string(res))
+			return nil
+		},
+	}
+}
+
+// GetCmdCheckInvariants checks the invariants of the multisig module
+func GetCmdCheckInvariants(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "check-invariants",
+		Short: "Check the invariants of the multisig module",
+		Long: `Check the invariants of the multisig module against the current state. A
+broken invariant can be reported with "msigcli tx crisis invariant-broken
+multisig [route]", which halts the chain.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc)
+
+			res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/invariants", queryRoute), nil)
+			if err != nil {
+				return err
+			}
+
+			var out types.QueryInvariants
+			cdc.MustUnmarshalJSON(res, &out)
+			return cliCtx.PrintOutput(out)
+		},
+	}
 }
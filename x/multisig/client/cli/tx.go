@@ -1,9 +1,11 @@
 package cli
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 
+	msigutils "github.com/cbarraford/cosmos-multisig/x/multisig/client/utils"
 	"github.com/cbarraford/cosmos-multisig/x/multisig/types"
 	"github.com/spf13/cobra"
 
@@ -13,6 +15,15 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/cosmos/cosmos-sdk/x/auth/client/utils"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+)
+
+// flags of the final multisig transaction of a transaction request, distinct
+// from the memo, fees and gas flags of the tx creating the request
+const (
+	flagRequestMemo = "request-memo"
+	flagRequestFees = "request-fees"
+	flagRequestGas  = "request-gas"
 )
 
 func GetTxCmd(storeKey string, cdc *codec.Codec) *cobra.Command {
@@ -27,8 +38,14 @@ func GetTxCmd(storeKey string, cdc *codec.Codec) *cobra.Command {
 	multisigTxCmd.AddCommand(client.PostCommands(
 		GetCmdCreateWallet(cdc),
 		GetCmdCreateTransaction(cdc),
+		GetCmdCreateMultiSendTransaction(cdc),
+		GetCmdCreateMsgsTransaction(cdc),
 		GetCmdSignTransaction(cdc),
-		GetCmdCompleteTransaction(cdc),
+		GetCmdSignRequest(storeKey, cdc),
+		GetCmdCompleteTransaction(storeKey, cdc),
+		GetCmdCancelTransaction(cdc),
+		GetCmdRejectTransaction(cdc),
+		GetCmdProposeWalletUpdate(cdc),
 	)...)
 
 	return multisigTxCmd
@@ -80,10 +97,15 @@ func GetCmdCreateWallet(cdc *codec.Codec) *cobra.Command {
 
 // GetCmdCreateTransaction is the CLI command for sending a CreateTransaction transaction
 func GetCmdCreateTransaction(cdc *codec.Codec) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "create-transaction [from] [to] [coins] [signers]",
 		Short: "create a new multi-signature transaction",
-		Args:  cobra.ExactArgs(4),
+		Long: `create a new multi-signature transaction request.
+
+The uuid assigned on-chain to the new transaction request is in the
+transaction_id tag of the tx, printed with the response in block mode
+(--broadcast-mode block).`,
+		Args: cobra.ExactArgs(4),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var err error
 			cliCtx := context.NewCLIContext().WithCodec(cdc).WithAccountDecoder(cdc)
@@ -114,10 +136,144 @@ func GetCmdCreateTransaction(cdc *codec.Codec) *cobra.Command {
 				}
 			}
 
-			msg := types.NewMsgCreateTransaction(from, to, coins[0].Amount, coins[0].Denom, signers)
+			memo, fee, err := requestFeeFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgCreateTransaction(from, to, coins, memo, fee, signers)
+			if err != nil {
+				return err
+			}
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			cliCtx.PrintResponse = true
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+	addRequestFlags(cmd)
+	return cmd
+}
+
+// GetCmdCreateMultiSendTransaction is the CLI command for sending a CreateMultiSendTransaction transaction
+func GetCmdCreateMultiSendTransaction(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create-multisend-transaction [from] [outputs] [signers]",
+		Short: "create a new multi-signature transaction to several recipients",
+		Long: `create a new multi-signature transaction request sending coins to several
+recipients.
+
+Outputs are semicolon separated recipient=coins pairs, ie
+"msig1...=10stake,3msigtoken;msig1...=5stake" (quote them in your shell).
+
+The uuid assigned on-chain to the new transaction request is in the
+transaction_id tag of the tx, printed with the response in block mode
+(--broadcast-mode block).`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			cliCtx := context.NewCLIContext().WithCodec(cdc).WithAccountDecoder(cdc)
+
+			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			from, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+
+			outputs, err := parseOutputs(args[1])
+			if err != nil {
+				return err
+			}
+
+			addrs := strings.Split(args[2], ",")
+			signers := make([]sdk.AccAddress, len(addrs))
+			for i, addr := range addrs {
+				signers[i], err = sdk.AccAddressFromBech32(addr)
+				if err != nil {
+					return err
+				}
+			}
+
+			memo, fee, err := requestFeeFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgCreateMultiSendTransaction(from, outputs, memo, fee, signers)
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			cliCtx.PrintResponse = true
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+	addRequestFlags(cmd)
+	return cmd
+}
+
+// GetCmdCreateMsgsTransaction is the CLI command for sending a CreateMsgsTransaction transaction
+func GetCmdCreateMsgsTransaction(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create-msgs-transaction [from] [tx-file] [signers]",
+		Short: "create a new multi-signature transaction of msgs of other modules",
+		Long: `create a new multi-signature transaction request for the msgs of an unsigned
+transaction, ie to delegate, undelegate, redelegate or withdraw rewards from the
+wallet. The unsigned transaction is generated with the --generate-only flag
+and the wallet address as --from, for example:
+
+$ msigcli tx staking delegate [validator] 10stake --from [wallet] --generate-only > delegate.json
+
+Every msg must only be signed by the wallet. Use - as tx-file to read it from
+stdin. The uuid of the new transaction request is in the transaction_id tag of
+the tx, printed with the response in block mode (--broadcast-mode block).`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			cliCtx := context.NewCLIContext().WithCodec(cdc).WithAccountDecoder(cdc)
+
+			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			from, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+
+			stdTx, err := utils.ReadStdTxFromFile(cdc, args[1])
+			if err != nil {
+				return err
+			}
+
+			addrs := strings.Split(args[2], ",")
+			signers := make([]sdk.AccAddress, len(addrs))
+			for i, addr := range addrs {
+				signers[i], err = sdk.AccAddressFromBech32(addr)
+				if err != nil {
+					return err
+				}
+			}
+
+			// the memo and fee of the unsigned transaction are used, unless
+			// given by flags
+			memo, fee, err := requestFeeFromFlags(cmd)
 			if err != nil {
 				return err
 			}
+			if !cmd.Flags().Changed(flagRequestMemo) {
+				memo = stdTx.Memo
+			}
+			if !cmd.Flags().Changed(flagRequestFees) && !cmd.Flags().Changed(flagRequestGas) {
+				fee = stdTx.Fee
+			}
+
+			msg := types.NewMsgCreateMsgsTransaction(from, stdTx.GetMsgs(), memo, fee, signers)
 			err = msg.ValidateBasic()
 			if err != nil {
 				return err
@@ -128,6 +284,30 @@ func GetCmdCreateTransaction(cdc *codec.Codec) *cobra.Command {
 			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
 		},
 	}
+	addRequestFlags(cmd)
+	return cmd
+}
+
+// parses semicolon separated recipient=coins pairs
+func parseOutputs(arg string) ([]bank.Output, error) {
+	pairs := strings.Split(arg, ";")
+	outputs := make([]bank.Output, len(pairs))
+	for i, pair := range pairs {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid output %q, expected recipient=coins", pair)
+		}
+		to, err := sdk.AccAddressFromBech32(strings.TrimSpace(parts[0]))
+		if err != nil {
+			return nil, err
+		}
+		coins, err := sdk.ParseCoins(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, err
+		}
+		outputs[i] = bank.NewOutput(to, coins)
+	}
+	return outputs, nil
 }
 
 // GetCmdSignTransaction is the CLI command for saving a transaction signature
@@ -142,9 +322,7 @@ func GetCmdSignTransaction(cdc *codec.Codec) *cobra.Command {
 
 			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
 
-			// TODO: compute base64 encoding of pubkey
-
-			addrs := strings.Split(args[3], ",")
+			addrs := strings.Split(args[4], ",")
 			signers := make([]sdk.AccAddress, len(addrs))
 			for i, addr := range addrs {
 				signers[i], err = sdk.AccAddressFromBech32(addr)
@@ -153,11 +331,39 @@ func GetCmdSignTransaction(cdc *codec.Codec) *cobra.Command {
 				}
 			}
 
-			msg := types.NewMsgSignTransaction(args[0], args[1], args[3], args[4], signers)
+			msg := types.NewMsgSignTransaction(args[0], args[1], args[2], args[3], signers)
+			err = msg.ValidateBasic()
 			if err != nil {
 				return err
 			}
-			err = msg.ValidateBasic()
+
+			cliCtx.PrintResponse = true
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
+// GetCmdSignRequest is the CLI command for signing a transaction request with
+// a key of the local keybase
+func GetCmdSignRequest(queryRoute string, cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "sign-request [uuid]",
+		Short: "Sign a transaction request with the --from key and save the signature",
+		Long: `Sign a transaction request with the --from key and save the signature.
+
+The stored request is fetched and its sign doc is signed with the --from key,
+which must belong to a member of the wallet. The pubkey encodings are derived
+from the key, and the signature is saved by a tx signed by the same key. The
+--chain-id must be the chain id of the network, as it is part of the sign doc.
+The passphrase of the key is asked for the signature, then for the tx.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliCtx := context.NewCLIContext().WithCodec(cdc).WithAccountDecoder(cdc)
+
+			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			msg, err := msigutils.BuildSignTransactionMsg(cliCtx, txBldr, queryRoute, args[0])
 			if err != nil {
 				return err
 			}
@@ -170,18 +376,23 @@ func GetCmdSignTransaction(cdc *codec.Codec) *cobra.Command {
 }
 
 // GetCmdCompleteTransaction is the CLI command for saving a transaction signature
-func GetCmdCompleteTransaction(cdc *codec.Codec) *cobra.Command {
+func GetCmdCompleteTransaction(queryRoute string, cdc *codec.Codec) *cobra.Command {
 	return &cobra.Command{
 		Use:   "complete-transaction [uuid] [transaction_id] [signers]",
 		Short: "Save a blockchain transaction id to a transaction",
-		Args:  cobra.ExactArgs(3),
+		Long: `Save a blockchain transaction id to a transaction.
+
+The tx is looked up by hash, it must be a successful tx with the msgs, fee and
+memo of the transaction request. Its height, gas used and result code are
+saved with the transaction id.`,
+		Args: cobra.ExactArgs(3),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var err error
 			cliCtx := context.NewCLIContext().WithCodec(cdc).WithAccountDecoder(cdc)
 
 			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
 
-			addrs := strings.Split(args[3], ",")
+			addrs := strings.Split(args[2], ",")
 			signers := make([]sdk.AccAddress, len(addrs))
 			for i, addr := range addrs {
 				signers[i], err = sdk.AccAddressFromBech32(addr)
@@ -190,10 +401,45 @@ func GetCmdCompleteTransaction(cdc *codec.Codec) *cobra.Command {
 				}
 			}
 
-			msg := types.NewMsgCompleteTransaction(args[0], args[1], signers)
+			msg, err := msigutils.BuildCompleteTransactionMsg(cliCtx, queryRoute, args[0], args[1], signers)
 			if err != nil {
 				return err
 			}
+
+			cliCtx.PrintResponse = true
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
+// GetCmdCancelTransaction is the CLI command for cancelling a transaction
+func GetCmdCancelTransaction(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "cancel-transaction [uuid] [signers]",
+		Short: "Withdraw a transaction request",
+		Long: `Withdraw a transaction request.
+
+The request is cancelled right away when signed by its creator, otherwise each
+signing wallet member votes to cancel it, and it is cancelled once the wallet
+threshold is reached.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			cliCtx := context.NewCLIContext().WithCodec(cdc).WithAccountDecoder(cdc)
+
+			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			addrs := strings.Split(args[1], ",")
+			signers := make([]sdk.AccAddress, len(addrs))
+			for i, addr := range addrs {
+				signers[i], err = sdk.AccAddressFromBech32(addr)
+				if err != nil {
+					return err
+				}
+			}
+
+			msg := types.NewMsgCancelTransaction(args[0], signers)
 			err = msg.ValidateBasic()
 			if err != nil {
 				return err
@@ -205,3 +451,125 @@ func GetCmdCompleteTransaction(cdc *codec.Codec) *cobra.Command {
 		},
 	}
 }
+
+// GetCmdRejectTransaction is the CLI command for rejecting a transaction
+func GetCmdRejectTransaction(cdc *codec.Codec) *cobra.Command {
+	return &cobra.Command{
+		Use:   "reject-transaction [uuid] [signers]",
+		Short: "Refuse to sign a transaction request",
+		Long: `Refuse to sign a transaction request.
+
+Each signing wallet member records a rejection, the request is rejected once
+enough members refused for the wallet threshold to be unreachable.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			cliCtx := context.NewCLIContext().WithCodec(cdc).WithAccountDecoder(cdc)
+
+			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			addrs := strings.Split(args[1], ",")
+			signers := make([]sdk.AccAddress, len(addrs))
+			for i, addr := range addrs {
+				signers[i], err = sdk.AccAddressFromBech32(addr)
+				if err != nil {
+					return err
+				}
+			}
+
+			msg := types.NewMsgRejectTransaction(args[0], signers)
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			cliCtx.PrintResponse = true
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+}
+
+// GetCmdProposeWalletUpdate is the CLI command for proposing a wallet update
+func GetCmdProposeWalletUpdate(cdc *codec.Codec) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "propose-wallet-update [wallet] [name] [min-signatures-required] [pub-keys] [signers]",
+		Short: "Propose or approve replacing a wallet by a new one",
+		Long: `Propose or approve replacing a wallet by a wallet with new public keys or
+threshold.
+
+Each signing wallet member approves the update. Proposing the same public keys
+and threshold as an existing proposal approves it. Once the wallet threshold is
+reached the new wallet is created, the old wallet is linked to it and a
+transaction request moving the balance of the old wallet to the new one is
+created. The uuid of that request is in the transaction_id tag of the tx,
+printed with the response in block mode (--broadcast-mode block).
+
+The fee of that transaction is set by --request-fees and --request-gas when the
+update is first proposed, and is deducted from the balance moved. Only coins
+of allowed denoms are moved.`,
+		Args: cobra.ExactArgs(5),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			cliCtx := context.NewCLIContext().WithCodec(cdc).WithAccountDecoder(cdc)
+
+			txBldr := auth.NewTxBuilderFromCLI().WithTxEncoder(utils.GetTxEncoder(cdc))
+
+			wallet, err := sdk.AccAddressFromBech32(args[0])
+			if err != nil {
+				return err
+			}
+
+			minSigs, err := strconv.ParseInt(args[2], 0, 64)
+			if err != nil {
+				return err
+			}
+
+			pubKeys := strings.Split(args[3], ",")
+
+			addrs := strings.Split(args[4], ",")
+			signers := make([]sdk.AccAddress, len(addrs))
+			for i, addr := range addrs {
+				signers[i], err = sdk.AccAddressFromBech32(addr)
+				if err != nil {
+					return err
+				}
+			}
+
+			_, fee, err := requestFeeFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+
+			msg := types.NewMsgProposeWalletUpdate(wallet, args[1], pubKeys, int(minSigs), fee, signers)
+			err = msg.ValidateBasic()
+			if err != nil {
+				return err
+			}
+
+			cliCtx.PrintResponse = true
+
+			return utils.GenerateOrBroadcastMsgs(cliCtx, txBldr, []sdk.Msg{msg})
+		},
+	}
+	cmd.Flags().String(flagRequestFees, "", "fees of the transaction moving the balance, paid by the wallet")
+	cmd.Flags().Uint64(flagRequestGas, 0, fmt.Sprintf("gas limit of the transaction moving the balance (default %d)", types.DefaultGas))
+	return cmd
+}
+
+func addRequestFlags(cmd *cobra.Command) {
+	cmd.Flags().String(flagRequestMemo, "", "memo of the multisig transaction")
+	cmd.Flags().String(flagRequestFees, "", "fees of the multisig transaction, paid by the wallet")
+	cmd.Flags().Uint64(flagRequestGas, 0, fmt.Sprintf("gas limit of the multisig transaction (default %d)", types.DefaultGas))
+}
+
+func requestFeeFromFlags(cmd *cobra.Command) (string, auth.StdFee, error) {
+	memo, _ := cmd.Flags().GetString(flagRequestMemo)
+	gas, _ := cmd.Flags().GetUint64(flagRequestGas)
+	fees, _ := cmd.Flags().GetString(flagRequestFees)
+	coins, err := sdk.ParseCoins(fees)
+	if err != nil {
+		return "", auth.StdFee{}, err
+	}
+	return memo, auth.NewStdFee(gas, coins), nil
+}
@@ -0,0 +1,65 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+
+	mtypes "github.com/cbarraford/cosmos-multisig/x/multisig/types"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/rest"
+)
+
+// the http status of each multisig error code
+var errorStatuses = map[sdk.CodeType]int{
+	mtypes.CodeWalletExists:        http.StatusConflict,
+	mtypes.CodeWalletNotFound:      http.StatusNotFound,
+	mtypes.CodeInvalidWallet:       http.StatusBadRequest,
+	mtypes.CodeWalletReplaced:      http.StatusConflict,
+	mtypes.CodeInvalidWalletUpdate: http.StatusBadRequest,
+	mtypes.CodeTxExists:            http.StatusConflict,
+	mtypes.CodeTxNotFound:          http.StatusNotFound,
+	mtypes.CodeInvalidTx:           http.StatusBadRequest,
+	mtypes.CodeNotMember:           http.StatusForbidden,
+	mtypes.CodeInvalidSigner:       http.StatusForbidden,
+	mtypes.CodeInvalidSignature:    http.StatusBadRequest,
+	mtypes.CodeThresholdNotMet:     http.StatusConflict,
+	mtypes.CodeAlreadyCompleted:    http.StatusConflict,
+	mtypes.CodeInvalidStatus:       http.StatusConflict,
+	mtypes.CodeConflictingVote:     http.StatusConflict,
+	mtypes.CodeTooManyPending:      http.StatusTooManyRequests,
+	mtypes.CodeDenomNotAllowed:     http.StatusBadRequest,
+	mtypes.CodeSequenceNotUsed:     http.StatusConflict,
+}
+
+// writeErrorResponse writes an error response with the http status of the
+// multisig error code of err, or the given status for other errors
+func writeErrorResponse(w http.ResponseWriter, status int, err error) {
+	rest.WriteErrorResponse(w, errorStatus(err, status), err.Error())
+}
+
+// returns the http status of a multisig error, either an sdk.Error returned by
+// ValidateBasic or the ABCI log of a failed query
+func errorStatus(err error, status int) int {
+	var codespace sdk.CodespaceType
+	var code sdk.CodeType
+	if sdkErr, ok := err.(sdk.Error); ok {
+		codespace, code = sdkErr.Codespace(), sdkErr.Code()
+	} else {
+		var abciErr struct {
+			Codespace sdk.CodespaceType `json:"codespace"`
+			Code      sdk.CodeType      `json:"code"`
+		}
+		if json.Unmarshal([]byte(err.Error()), &abciErr) != nil {
+			return status
+		}
+		codespace, code = abciErr.Codespace, abciErr.Code
+	}
+	if codespace != mtypes.DefaultCodespace {
+		return status
+	}
+	if s, ok := errorStatuses[code]; ok {
+		return s
+	}
+	return status
+}
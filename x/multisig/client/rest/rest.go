@@ -1,22 +1,23 @@
 package rest
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
-	"log"
 	"net/http"
-	"strings"
 
+	msigutils "github.com/cbarraford/cosmos-multisig/x/multisig/client/utils"
+	"github.com/cbarraford/cosmos-multisig/x/multisig/tags"
 	mtypes "github.com/cbarraford/cosmos-multisig/x/multisig/types"
 
 	"github.com/cosmos/cosmos-sdk/client/context"
-	"github.com/cosmos/cosmos-sdk/client/flags"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/rest"
 	"github.com/cosmos/cosmos-sdk/x/auth/client/utils"
 	"github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/cosmos/cosmos-sdk/x/bank"
 
 	"github.com/gorilla/mux"
 )
@@ -33,17 +34,25 @@ var (
 // RegisterRoutes - Central function to define routes that get registered by the main application
 func RegisterRoutes(cliCtx context.CLIContext, r *mux.Router, storeName string) {
 	r.HandleFunc(fmt.Sprintf("/%s/wallet/{%s}", storeName, walletAddress), getWalletHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/wallet/{%s}/updates", storeName, walletAddress), walletUpdatesHandler(cliCtx, storeName)).Methods("GET")
 	r.HandleFunc(fmt.Sprintf("/%s/transaction/{%s}", storeName, transactionID), getTransactionHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/transaction/{%s}/unsigned-tx", storeName, transactionID), unsignedTxHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/transaction/{%s}/sign-doc", storeName, transactionID), signDocHandler(cliCtx, storeName)).Methods("GET")
 
 	r.HandleFunc(fmt.Sprintf("/%s/wallets/{%s}", storeName, walletAddress), walletsHandler(cliCtx, storeName)).Methods("GET")
 	r.HandleFunc(fmt.Sprintf("/%s/transactions/{%s}", storeName, transactionID), transactionsHandler(cliCtx, storeName)).Methods("GET")
+	r.HandleFunc(fmt.Sprintf("/%s/params", storeName), paramsHandler(cliCtx, storeName)).Methods("GET")
 
 	r.HandleFunc(fmt.Sprintf("/%s/wallet", storeName), createWalletHandler(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/wallet/update", storeName), proposeWalletUpdateHandler(cliCtx)).Methods("POST")
 	r.HandleFunc(fmt.Sprintf("/%s/transaction", storeName), createTransactionHandler(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/transaction/multisend", storeName), createMultiSendTransactionHandler(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/transaction/msgs", storeName), createMsgsTransactionHandler(cliCtx)).Methods("POST")
 	r.HandleFunc(fmt.Sprintf("/%s/transaction/sign", storeName), signTransactionHandler(cliCtx)).Methods("POST")
-	r.HandleFunc(fmt.Sprintf("/%s/transaction/complete", storeName), completeTransactionHandler(cliCtx)).Methods("POST")
-	//r.HandleFunc(fmt.Sprintf("/%s/tx", storeName), createUnsignedTransactionHandler(cliCtx)).Methods("PUT")
-	r.HandleFunc(fmt.Sprintf("/%s/sign/multi", storeName), multiSignHandler(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/transaction/complete", storeName), completeTransactionHandler(cliCtx, storeName)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/transaction/cancel", storeName), cancelTransactionHandler(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/transaction/reject", storeName), rejectTransactionHandler(cliCtx)).Methods("POST")
+	r.HandleFunc(fmt.Sprintf("/%s/sign/multi", storeName), multiSignHandler(cliCtx, storeName)).Methods("POST")
 
 	r.HandleFunc(fmt.Sprintf("/%s/broadcast", storeName), broadcastTxRequest(cliCtx)).Methods("POST")
 }
@@ -55,7 +64,22 @@ func getWalletHandler(cliCtx context.CLIContext, storeName string) http.HandlerF
 
 		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/getWallet/%s", storeName, paramType), nil)
 		if err != nil {
-			rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+			writeErrorResponse(w, http.StatusNotFound, err)
+			return
+		}
+
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+func walletUpdatesHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		paramType := vars[walletAddress]
+
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/listWalletUpdates/%s", storeName, paramType), nil)
+		if err != nil {
+			writeErrorResponse(w, http.StatusNotFound, err)
 			return
 		}
 
@@ -70,7 +94,7 @@ func getTransactionHandler(cliCtx context.CLIContext, storeName string) http.Han
 
 		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/getTransaction/%s", storeName, paramType), nil)
 		if err != nil {
-			rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+			writeErrorResponse(w, http.StatusNotFound, err)
 			return
 		}
 
@@ -78,15 +102,63 @@ func getTransactionHandler(cliCtx context.CLIContext, storeName string) http.Han
 	}
 }
 
+func unsignedTxHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		paramType := vars[transactionID]
+
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/getUnsignedTx/%s", storeName, paramType), nil)
+		if err != nil {
+			writeErrorResponse(w, http.StatusNotFound, err)
+			return
+		}
+
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+// the sign doc is written as is, since its bytes are what members sign
+func signDocHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		paramType := vars[transactionID]
+
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/getSignDoc/%s", storeName, paramType), nil)
+		if err != nil {
+			writeErrorResponse(w, http.StatusNotFound, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(res)
+	}
+}
+
 func walletsHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
 		paramType := vars[walletAddress]
-		log.Printf("Param %+v", paramType)
 
-		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/listWallets/%s", storeName, paramType), nil)
+		page, limit, ok := parsePage(w, r)
+		if !ok {
+			return
+		}
+		params := mtypes.NewQueryWalletsParams(page, limit)
+		if err := params.ValidateBasic(); err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, err)
+			return
+		}
+
+		bz, err := cliCtx.Codec.MarshalJSON(params)
+		if err != nil {
+			writeErrorResponse(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/listWallets/%s", storeName, paramType), bz)
 		if err != nil {
-			rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+			writeErrorResponse(w, http.StatusNotFound, err)
 			return
 		}
 		rest.PostProcessResponse(w, cliCtx, res)
@@ -97,10 +169,86 @@ func transactionsHandler(cliCtx context.CLIContext, storeName string) http.Handl
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
 		paramType := vars[transactionID]
+		query := r.URL.Query()
+
+		page, limit, ok := parsePage(w, r)
+		if !ok {
+			return
+		}
+
+		var minHeight, maxHeight int64
+		if v := query.Get("min_height"); v != "" {
+			if minHeight, ok = rest.ParseInt64OrReturnBadRequest(w, v); !ok {
+				return
+			}
+		}
+		if v := query.Get("max_height"); v != "" {
+			if maxHeight, ok = rest.ParseInt64OrReturnBadRequest(w, v); !ok {
+				return
+			}
+		}
+
+		var recipient sdk.AccAddress
+		if v := query.Get("recipient"); v != "" {
+			var err error
+			recipient, err = sdk.AccAddressFromBech32(v)
+			if err != nil {
+				writeErrorResponse(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+
+		params := mtypes.NewQueryTransactionsParams(
+			page, limit, query.Get("sort"), query.Get("status"),
+			minHeight, maxHeight, recipient, query.Get("denom"),
+		)
+		if err := params.ValidateBasic(); err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, err)
+			return
+		}
+
+		bz, err := cliCtx.Codec.MarshalJSON(params)
+		if err != nil {
+			writeErrorResponse(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/listTransactions/%s", storeName, paramType), bz)
+		if err != nil {
+			writeErrorResponse(w, http.StatusNotFound, err)
+			return
+		}
+		rest.PostProcessResponse(w, cliCtx, res)
+	}
+}
+
+// parses the page and limit query parameters, a missing limit returns all
+// results
+func parsePage(w http.ResponseWriter, r *http.Request) (page, limit int, ok bool) {
+	query := r.URL.Query()
+	page = rest.DefaultPage
+	if v := query.Get("page"); v != "" {
+		n, ok := rest.ParseInt64OrReturnBadRequest(w, v)
+		if !ok {
+			return 0, 0, false
+		}
+		page = int(n)
+	}
+	if v := query.Get("limit"); v != "" {
+		n, ok := rest.ParseInt64OrReturnBadRequest(w, v)
+		if !ok {
+			return 0, 0, false
+		}
+		limit = int(n)
+	}
+	return page, limit, true
+}
 
-		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/listTransactions/%s", storeName, paramType), nil)
+func paramsHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/params", storeName), nil)
 		if err != nil {
-			rest.WriteErrorResponse(w, http.StatusNotFound, err.Error())
+			writeErrorResponse(w, http.StatusInternalServerError, err)
 			return
 		}
 		rest.PostProcessResponse(w, cliCtx, res)
@@ -108,11 +256,15 @@ func transactionsHandler(cliCtx context.CLIContext, storeName string) http.Handl
 }
 
 type multiSign struct {
-	Signatures []string `json:"signatures"`
-	Slots      string   `json:"slots"`
+	Address    sdk.AccAddress     `json:"address"`
+	Signatures []mtypes.Signature `json:"signatures"`
 }
 
-func multiSignHandler(cliCtx context.CLIContext) http.HandlerFunc {
+type multiSignResponse struct {
+	Signature string `json:"signature"`
+}
+
+func multiSignHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req multiSign
 		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
@@ -120,101 +272,95 @@ func multiSignHandler(cliCtx context.CLIContext) http.HandlerFunc {
 			return
 		}
 
-		// Important: The input signature must be in order relative to their
-		// public key was in the list of public keys were used to create the
-		// multisig wallet.
-		signatures := make([]string, len(req.Signatures))
-		for i, siggy := range req.Signatures {
-			if i < (len(req.Signatures) - 1) {
-				sig := []byte(siggy)
-				// since we are not the last signature, make edits...
-				// remove the '==' at the end of the string
-				sig = sig[:len(sig)-2]
-				// increment the last character by 1
-				sig[len(sig)-1] += 1
-				signatures[i] = string(sig)
-			} else {
-				signatures[i] = siggy
-			}
+		if req.Address.Empty() {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "wallet address cannot be empty")
+			return
 		}
 
-		var totalPrefix string
-		switch len(req.Slots) {
-		case 2:
-			totalPrefix = "Ah"
-			req.Slots = fmt.Sprintf("%s0", req.Slots)
-		case 3:
-			totalPrefix = "Ax"
-		//case 4:
-		//totalPrefix = "BB"
-		default:
-			rest.WriteErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("Number of public keys (%d) in this wallet is not currently supported", len(req.Slots)))
-		}
-
-		var sigPrefix string
-		switch req.Slots {
-		case "001":
-			sigPrefix = "IB"
-		case "010":
-			sigPrefix = "QB"
-		case "011":
-			sigPrefix = "YB"
-		case "100":
-			sigPrefix = "qB"
-		case "101":
-			sigPrefix = "oB"
-		case "110":
-			sigPrefix = "wB"
-		case "111":
-			sigPrefix = "4B"
-		default:
-			rest.WriteErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("Number of public keys (%d) in this wallet is not currently supported", len(req.Slots)))
-		}
-
-		prefix := fmt.Sprintf("CgUI%sIB%s", totalPrefix, sigPrefix)
-
-		signatures = append([]string{prefix}, signatures...)
-		multisignature := strings.Join(signatures[:], "JA")
+		res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/getWallet/%s", storeName, req.Address), nil)
+		if err != nil {
+			writeErrorResponse(w, http.StatusNotFound, err)
+			return
+		}
+
+		var wallet mtypes.MultiSigWallet
+		cliCtx.Codec.MustUnmarshalJSON(res, &wallet)
+
+		// signatures are placed in the multisignature according to the
+		// position of their public key in the wallet, so order does not matter
+		multisignature, err := wallet.Multisign(req.Signatures)
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, err)
+			return
+		}
 
 		w.Header().Set("Content-Type", "application/json; charset=utf-8")
 		w.WriteHeader(http.StatusOK)
-		io.WriteString(w, fmt.Sprintf(`{"signature":"%s"}`, multisignature))
+		j, _ := json.Marshal(multiSignResponse{
+			Signature: base64.StdEncoding.EncodeToString(multisignature),
+		})
+		io.WriteString(w, string(j))
 	}
 }
 
-type createUnsignedTransaction struct {
-	From   string `json:"from"`
-	To     string `json:"to"`
-	Amount string `json:"amount"`
-	Memo   string `json:"memo"`
+type createTransaction struct {
+	BaseReq rest.BaseReq   `json:"base_req"`
+	From    sdk.AccAddress `json:"from"`
+	To      sdk.AccAddress `json:"to"`
+	Coins   sdk.Coins      `json:"coins"`
+	Memo    string         `json:"memo"`
+	Fee     types.StdFee   `json:"fee"`
+	Signers []string       `json:"signers"`
 }
 
-type msg struct {
-	Type  string `json:"type"`
-	Value value2 `json:"value"`
-}
+func createTransactionHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req createTransaction
+		var err error
 
-type value2 struct {
-	FromAddress string    `json:"from_address"`
-	ToAddress   string    `json:"to_address"`
-	Amount      sdk.Coins `json:"amount"`
-}
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		baseReq := req.BaseReq.Sanitize()
+		if !baseReq.ValidateBasic(w) {
+			return
+		}
 
-type value1 struct {
-	Msgs       []msg        `json:"msg"`
-	Fee        types.StdFee `json:"fee"`
-	Signatures []string     `json:"signatures"`
-	Memo       string       `json:"memo"`
+		signers := make([]sdk.AccAddress, len(req.Signers))
+		for i, _ := range req.Signers {
+			signers[i], err = sdk.AccAddressFromBech32(req.Signers[i])
+			if err != nil {
+				writeErrorResponse(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+
+		// create the message
+		msg := mtypes.NewMsgCreateTransaction(req.From, req.To, req.Coins.Sort(), req.Memo, req.Fee, signers)
+		err = msg.ValidateBasic()
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, err)
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, baseReq, []sdk.Msg{msg})
+	}
 }
 
-type unsignedTransaction struct {
-	Type  string `json:"type"`
-	Value value1 `json:"value"`
+type createMultiSendTransaction struct {
+	BaseReq rest.BaseReq   `json:"base_req"`
+	From    sdk.AccAddress `json:"from"`
+	Outputs []bank.Output  `json:"outputs"`
+	Memo    string         `json:"memo"`
+	Fee     types.StdFee   `json:"fee"`
+	Signers []string       `json:"signers"`
 }
 
-func createUnsignedTransactionHandler(cliCtx context.CLIContext) http.HandlerFunc {
+func createMultiSendTransactionHandler(cliCtx context.CLIContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var req createUnsignedTransaction
+		var req createMultiSendTransaction
 		var err error
 
 		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
@@ -222,62 +368,49 @@ func createUnsignedTransactionHandler(cliCtx context.CLIContext) http.HandlerFun
 			return
 		}
 
-		_, err = sdk.AccAddressFromBech32(req.From)
-		if err != nil {
-			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+		baseReq := req.BaseReq.Sanitize()
+		if !baseReq.ValidateBasic(w) {
 			return
 		}
 
-		_, err = sdk.AccAddressFromBech32(req.To)
-		if err != nil {
-			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
-			return
+		signers := make([]sdk.AccAddress, len(req.Signers))
+		for i := range req.Signers {
+			signers[i], err = sdk.AccAddressFromBech32(req.Signers[i])
+			if err != nil {
+				writeErrorResponse(w, http.StatusBadRequest, err)
+				return
+			}
 		}
 
-		coins, err := sdk.ParseCoins(req.Amount)
-		if err != nil {
-			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
-			return
+		outputs := make([]bank.Output, len(req.Outputs))
+		for i, output := range req.Outputs {
+			outputs[i] = bank.NewOutput(output.Address, output.Coins.Sort())
 		}
 
-		tx := unsignedTransaction{
-			Type: "auth/StdTx",
-			Value: value1{
-				Msgs: []msg{
-					{
-						Type: "cosmos-sdk/MsgSend",
-						Value: value2{
-							FromAddress: req.From,
-							ToAddress:   req.To,
-							Amount:      coins,
-						},
-					},
-				},
-				Fee:        types.NewStdFee(flags.DefaultGasLimit, sdk.Coins{}),
-				Signatures: nil,
-				Memo:       req.Memo,
-			},
+		// create the message
+		msg := mtypes.NewMsgCreateMultiSendTransaction(req.From, outputs, req.Memo, req.Fee, signers)
+		err = msg.ValidateBasic()
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, err)
+			return
 		}
 
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		j, _ := json.Marshal(tx)
-		io.WriteString(w, string(j))
+		utils.WriteGenerateStdTxResponse(w, cliCtx, baseReq, []sdk.Msg{msg})
 	}
 }
 
-type createTransaction struct {
+type createMsgsTransaction struct {
 	BaseReq rest.BaseReq   `json:"base_req"`
 	From    sdk.AccAddress `json:"from"`
-	To      sdk.AccAddress `json:"to"`
-	Amount  sdk.Int        `json:"amount"`
-	Denom   string         `json:"denom"`
+	Msgs    []sdk.Msg      `json:"msgs"`
+	Memo    string         `json:"memo"`
+	Fee     types.StdFee   `json:"fee"`
 	Signers []string       `json:"signers"`
 }
 
-func createTransactionHandler(cliCtx context.CLIContext) http.HandlerFunc {
+func createMsgsTransactionHandler(cliCtx context.CLIContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		var req createTransaction
+		var req createMsgsTransaction
 		var err error
 
 		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
@@ -291,19 +424,19 @@ func createTransactionHandler(cliCtx context.CLIContext) http.HandlerFunc {
 		}
 
 		signers := make([]sdk.AccAddress, len(req.Signers))
-		for i, _ := range req.Signers {
+		for i := range req.Signers {
 			signers[i], err = sdk.AccAddressFromBech32(req.Signers[i])
 			if err != nil {
-				rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+				writeErrorResponse(w, http.StatusBadRequest, err)
 				return
 			}
 		}
 
 		// create the message
-		msg := mtypes.NewMsgCreateTransaction(req.From, req.To, req.Amount, req.Denom, signers)
+		msg := mtypes.NewMsgCreateMsgsTransaction(req.From, req.Msgs, req.Memo, req.Fee, signers)
 		err = msg.ValidateBasic()
 		if err != nil {
-			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			writeErrorResponse(w, http.StatusBadRequest, err)
 			return
 		}
 
@@ -340,7 +473,7 @@ func signTransactionHandler(cliCtx context.CLIContext) http.HandlerFunc {
 		for i, _ := range req.Signers {
 			signers[i], err = sdk.AccAddressFromBech32(req.Signers[i])
 			if err != nil {
-				rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+				writeErrorResponse(w, http.StatusBadRequest, err)
 				return
 			}
 		}
@@ -348,7 +481,7 @@ func signTransactionHandler(cliCtx context.CLIContext) http.HandlerFunc {
 		msg := mtypes.NewMsgSignTransaction(req.UUID, req.PubKey, req.PubKeyBase64, req.Signature, signers)
 		err = msg.ValidateBasic()
 		if err != nil {
-			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			writeErrorResponse(w, http.StatusBadRequest, err)
 			return
 		}
 
@@ -363,7 +496,7 @@ type completeTransaction struct {
 	Signers []string     `json:"signers"`
 }
 
-func completeTransactionHandler(cliCtx context.CLIContext) http.HandlerFunc {
+func completeTransactionHandler(cliCtx context.CLIContext, storeName string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req completeTransaction
 		var err error
@@ -383,16 +516,99 @@ func completeTransactionHandler(cliCtx context.CLIContext) http.HandlerFunc {
 		for i, _ := range req.Signers {
 			signers[i], err = sdk.AccAddressFromBech32(req.Signers[i])
 			if err != nil {
-				rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+				writeErrorResponse(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+
+		// create the message, once the tx is verified
+		msg, err := msigutils.BuildCompleteTransactionMsg(cliCtx, storeName, req.UUID, req.TxID, signers)
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, err)
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, baseReq, []sdk.Msg{msg})
+	}
+}
+
+type cancelTransaction struct {
+	BaseReq rest.BaseReq `json:"base_req"`
+	UUID    string       `json:"uuid"`
+	Signers []string     `json:"signers"`
+}
+
+func cancelTransactionHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req cancelTransaction
+		var err error
+
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		baseReq := req.BaseReq.Sanitize()
+		if !baseReq.ValidateBasic(w) {
+			return
+		}
+
+		signers := make([]sdk.AccAddress, len(req.Signers))
+		for i := range req.Signers {
+			signers[i], err = sdk.AccAddressFromBech32(req.Signers[i])
+			if err != nil {
+				writeErrorResponse(w, http.StatusBadRequest, err)
 				return
 			}
 		}
 
 		// create the message
-		msg := mtypes.NewMsgCompleteTransaction(req.UUID, req.TxID, signers)
+		msg := mtypes.NewMsgCancelTransaction(req.UUID, signers)
 		err = msg.ValidateBasic()
 		if err != nil {
-			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			writeErrorResponse(w, http.StatusBadRequest, err)
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, baseReq, []sdk.Msg{msg})
+	}
+}
+
+type rejectTransaction struct {
+	BaseReq rest.BaseReq `json:"base_req"`
+	UUID    string       `json:"uuid"`
+	Signers []string     `json:"signers"`
+}
+
+func rejectTransactionHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req rejectTransaction
+		var err error
+
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		baseReq := req.BaseReq.Sanitize()
+		if !baseReq.ValidateBasic(w) {
+			return
+		}
+
+		signers := make([]sdk.AccAddress, len(req.Signers))
+		for i := range req.Signers {
+			signers[i], err = sdk.AccAddressFromBech32(req.Signers[i])
+			if err != nil {
+				writeErrorResponse(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+
+		// create the message
+		msg := mtypes.NewMsgRejectTransaction(req.UUID, signers)
+		err = msg.ValidateBasic()
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, err)
 			return
 		}
 
@@ -428,7 +644,7 @@ func createWalletHandler(cliCtx context.CLIContext) http.HandlerFunc {
 		for i, _ := range req.Signers {
 			signers[i], err = sdk.AccAddressFromBech32(req.Signers[i])
 			if err != nil {
-				rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+				writeErrorResponse(w, http.StatusBadRequest, err)
 				return
 			}
 		}
@@ -437,7 +653,59 @@ func createWalletHandler(cliCtx context.CLIContext) http.HandlerFunc {
 		msg := mtypes.NewMsgCreateWallet(req.Name, req.PubKeys, req.MinSigTx, signers)
 		err = msg.ValidateBasic()
 		if err != nil {
-			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			writeErrorResponse(w, http.StatusBadRequest, err)
+			return
+		}
+
+		utils.WriteGenerateStdTxResponse(w, cliCtx, baseReq, []sdk.Msg{msg})
+	}
+}
+
+type proposeWalletUpdate struct {
+	BaseReq  rest.BaseReq `json:"base_req"`
+	Wallet   string       `json:"wallet"`
+	Name     string       `json:"name"`
+	MinSigTx int          `json:"min_sig_tx"`
+	PubKeys  []string     `json:"pub_keys"`
+	Fee      types.StdFee `json:"fee"`
+	Signers  []string     `json:"signers"`
+}
+
+func proposeWalletUpdateHandler(cliCtx context.CLIContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req proposeWalletUpdate
+		var err error
+
+		if !rest.ReadRESTReq(w, r, cliCtx.Codec, &req) {
+			rest.WriteErrorResponse(w, http.StatusBadRequest, "failed to parse request")
+			return
+		}
+
+		baseReq := req.BaseReq.Sanitize()
+		if !baseReq.ValidateBasic(w) {
+			return
+		}
+
+		wallet, err := sdk.AccAddressFromBech32(req.Wallet)
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, err)
+			return
+		}
+
+		signers := make([]sdk.AccAddress, len(req.Signers))
+		for i := range req.Signers {
+			signers[i], err = sdk.AccAddressFromBech32(req.Signers[i])
+			if err != nil {
+				writeErrorResponse(w, http.StatusBadRequest, err)
+				return
+			}
+		}
+
+		// create the message
+		msg := mtypes.NewMsgProposeWalletUpdate(wallet, req.Name, req.PubKeys, req.MinSigTx, req.Fee, signers)
+		err = msg.ValidateBasic()
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, err)
 			return
 		}
 
@@ -471,19 +739,19 @@ func broadcastTxRequest(cliCtx context.CLIContext) http.HandlerFunc {
 
 		body, err := ioutil.ReadAll(r.Body)
 		if err != nil {
-			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			writeErrorResponse(w, http.StatusBadRequest, err)
 			return
 		}
 
 		err = cliCtx.Codec.UnmarshalJSON(body, &req)
 		if err != nil {
-			rest.WriteErrorResponse(w, http.StatusBadRequest, err.Error())
+			writeErrorResponse(w, http.StatusBadRequest, err)
 			return
 		}
 
 		txBytes, err := cliCtx.Codec.MarshalBinaryLengthPrefixed(req.Tx)
 		if err != nil {
-			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			writeErrorResponse(w, http.StatusInternalServerError, err)
 			return
 		}
 
@@ -491,10 +759,41 @@ func broadcastTxRequest(cliCtx context.CLIContext) http.HandlerFunc {
 
 		res, err := cliCtx.BroadcastTx(txBytes)
 		if err != nil {
-			rest.WriteErrorResponse(w, http.StatusInternalServerError, err.Error())
+			writeErrorResponse(w, http.StatusInternalServerError, err)
 			return
 		}
 
-		rest.PostProcessResponse(w, cliCtx, res)
+		uid := msigutils.TransactionID(res)
+		if uid == "" {
+			rest.PostProcessResponse(w, cliCtx, res)
+			return
+		}
+		// the uuid of the transaction request is added to the response
+		bz, err := withTransactionID(cliCtx, res, uid)
+		if err != nil {
+			writeErrorResponse(w, http.StatusInternalServerError, err)
+			return
+		}
+		rest.PostProcessResponse(w, cliCtx, bz)
+	}
+}
+
+// returns the JSON of a tx response with a transaction_id field
+func withTransactionID(cliCtx context.CLIContext, res sdk.TxResponse, uid string) ([]byte, error) {
+	bz, err := cliCtx.Codec.MarshalJSON(res)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(bz, &fields); err != nil {
+		return nil, err
+	}
+	fields[tags.TransactionID], err = json.Marshal(uid)
+	if err != nil {
+		return nil, err
+	}
+	if cliCtx.Indent {
+		return json.MarshalIndent(fields, "", "  ")
 	}
+	return json.Marshal(fields)
 }
@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/cbarraford/cosmos-multisig/x/multisig/tags"
+	"github.com/cbarraford/cosmos-multisig/x/multisig/types"
+	"github.com/cosmos/cosmos-sdk/client/context"
+	"github.com/cosmos/cosmos-sdk/client/keys"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	authutils "github.com/cosmos/cosmos-sdk/x/auth/client/utils"
+)
+
+// QueryTransaction returns a stored transaction request, flagged as stale when
+// its sequence no longer matches the account of its wallet
+func QueryTransaction(cliCtx context.CLIContext, queryRoute, uid string) (types.QueryTransaction, error) {
+	var transaction types.QueryTransaction
+	res, _, err := cliCtx.QueryWithData(fmt.Sprintf("custom/%s/getTransaction/%s", queryRoute, uid), nil)
+	if err != nil {
+		return transaction, err
+	}
+	err = cliCtx.Codec.UnmarshalJSON(res, &transaction)
+	return transaction, err
+}
+
+// BuildCompleteTransactionMsg looks up the tx of the given hash, checks it is
+// the successful broadcast of the transaction request, and returns the msg
+// completing the request with the height, gas used and result code of the tx
+func BuildCompleteTransactionMsg(cliCtx context.CLIContext, queryRoute, uid, txHash string, signers []sdk.AccAddress) (types.MsgCompleteTransaction, error) {
+	queried, err := QueryTransaction(cliCtx, queryRoute, uid)
+	if err != nil {
+		return types.MsgCompleteTransaction{}, err
+	}
+
+	res, err := authutils.QueryTx(cliCtx, txHash)
+	if err != nil {
+		return types.MsgCompleteTransaction{}, err
+	}
+	if err = queried.Transaction.VerifyTxResponse(res); err != nil {
+		return types.MsgCompleteTransaction{}, err
+	}
+
+	msg := types.NewMsgCompleteTransaction(uid, res.TxHash, res.Height, res.GasUsed, res.Code, signers)
+	return msg, msg.ValidateBasic()
+}
+
+// BuildSignTransactionMsg signs the sign doc of a transaction request with the
+// key given by --from, and returns the msg saving the signature, submitted by
+// that key. The sign doc of a stale pending request is built for the current
+// account number and sequence of the wallet, which the request is pinned to
+// again when it is signed.
+func BuildSignTransactionMsg(cliCtx context.CLIContext, txBldr auth.TxBuilder, queryRoute, uid string) (types.MsgSignTransaction, error) {
+	queried, err := QueryTransaction(cliCtx, queryRoute, uid)
+	if err != nil {
+		return types.MsgSignTransaction{}, err
+	}
+	transaction := queried.Transaction
+	if !transaction.Status.IsSignable() {
+		return types.MsgSignTransaction{}, fmt.Errorf("transaction can no longer be signed (%s)", transaction.Status)
+	}
+	if queried.Stale && transaction.Status == types.StatusReadyToBroadcast {
+		return types.MsgSignTransaction{}, fmt.Errorf("transaction sequence has been used, complete or cancel the transaction")
+	}
+	if queried.Stale {
+		acc, err := cliCtx.GetAccount(transaction.From)
+		if err != nil {
+			return types.MsgSignTransaction{}, err
+		}
+		transaction.Repin(acc.GetAccountNumber(), acc.GetSequence())
+	}
+
+	name := cliCtx.GetFromName()
+	passphrase, err := keys.GetPassphrase(name)
+	if err != nil {
+		return types.MsgSignTransaction{}, err
+	}
+	sig, pubkey, err := txBldr.Keybase().Sign(name, passphrase, transaction.GetSignBytes(txBldr.ChainID()))
+	if err != nil {
+		return types.MsgSignTransaction{}, err
+	}
+	bech32, pubkeyBase64, err := types.EncodePubKey(pubkey)
+	if err != nil {
+		return types.MsgSignTransaction{}, err
+	}
+
+	msg := types.NewMsgSignTransaction(
+		uid,
+		bech32,
+		pubkeyBase64,
+		base64.StdEncoding.EncodeToString(sig),
+		[]sdk.AccAddress{cliCtx.GetFromAddress()},
+	)
+	return msg, msg.ValidateBasic()
+}
+
+// TransactionID returns the uuid of the transaction request in the
+// transaction_id tag of a tx response, or an empty string. Tags are only
+// returned once the tx is included in a block.
+func TransactionID(res sdk.TxResponse) string {
+	for _, tag := range res.Tags {
+		if tag.Key == tags.TransactionID {
+			return tag.Value
+		}
+	}
+	return ""
+}
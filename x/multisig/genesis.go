@@ -1,29 +1,193 @@
 package multisig
 
 import (
+	"fmt"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	abci "github.com/tendermint/tendermint/abci/types"
 )
 
+// TransactionCount is the number of transactions created for a wallet, used
+// to derive the uuid of its next transaction
+type TransactionCount struct {
+	Address sdk.AccAddress `json:"address"`
+	Count   uint64         `json:"count"`
+}
+
 type GenesisState struct {
+	Params            Params             `json:"params"`
+	Wallets           []MultiSigWallet   `json:"wallets"`
+	Transactions      []Transaction      `json:"transactions"`
+	TransactionCounts []TransactionCount `json:"transaction_counts"`
+	WalletUpdates     []WalletUpdate     `json:"wallet_updates"`
 }
 
-func NewGenesisState() GenesisState {
-	return GenesisState{}
+func NewGenesisState(params Params, wallets []MultiSigWallet, transactions []Transaction, counts []TransactionCount, updates []WalletUpdate) GenesisState {
+	return GenesisState{
+		Params:            params,
+		Wallets:           wallets,
+		Transactions:      transactions,
+		TransactionCounts: counts,
+		WalletUpdates:     updates,
+	}
 }
 
 func ValidateGenesis(data GenesisState) error {
+	if err := data.Params.Validate(); err != nil {
+		return err
+	}
+
+	wallets := make(map[string]MultiSigWallet)
+	for _, wallet := range data.Wallets {
+		if _, ok := wallets[wallet.Address.String()]; ok {
+			return fmt.Errorf("duplicate wallet address %s", wallet.Address)
+		}
+		// recreating the wallet checks the threshold and pubkeys
+		expected, err := NewMultiSigWallet(wallet.Name, wallet.PubKeys, wallet.MinSigTx)
+		if err != nil {
+			return fmt.Errorf("invalid wallet %s: %s", wallet.Address, err.Error())
+		}
+		if !expected.Address.Equals(wallet.Address) {
+			return fmt.Errorf("invalid wallet %s: address does not match pubkeys (%s)", wallet.Address, expected.Address)
+		}
+		wallets[wallet.Address.String()] = wallet
+	}
+	for _, wallet := range data.Wallets {
+		if wallet.Successor.Empty() {
+			continue
+		}
+		if _, ok := wallets[wallet.Successor.String()]; !ok {
+			return fmt.Errorf("wallet %s references unknown successor %s", wallet.Address, wallet.Successor)
+		}
+	}
+
+	uids := make(map[string]bool)
+	for _, transaction := range data.Transactions {
+		if transaction.UUID == "" {
+			return fmt.Errorf("transaction from %s has no uuid", transaction.From)
+		}
+		if uids[transaction.UUID] {
+			return fmt.Errorf("duplicate transaction uuid %s", transaction.UUID)
+		}
+		uids[transaction.UUID] = true
+		wallet, ok := wallets[transaction.From.String()]
+		if !ok {
+			return fmt.Errorf("transaction %s references unknown wallet %s", transaction.UUID, transaction.From)
+		}
+		if transaction.To.Empty() && !transaction.IsMultiSend() && !transaction.HasMsgs() {
+			return fmt.Errorf("transaction %s has no recipient", transaction.UUID)
+		}
+		if !transaction.Coins.IsValid() {
+			return fmt.Errorf("transaction %s has invalid coins %s", transaction.UUID, transaction.Coins)
+		}
+		if !transaction.Fee.Amount.IsValid() {
+			return fmt.Errorf("transaction %s has an invalid fee %s", transaction.UUID, transaction.Fee.Amount)
+		}
+		for _, output := range transaction.Outputs {
+			if err := output.ValidateBasic(); err != nil {
+				return fmt.Errorf("transaction %s has an invalid output: %s", transaction.UUID, err.Error())
+			}
+		}
+		if err := ValidateWalletMsgs(transaction.From, transaction.Msgs); err != nil {
+			return fmt.Errorf("transaction %s has an invalid msg: %s", transaction.UUID, err.Error())
+		}
+		if transaction.IsMultiSend() {
+			// IsEqual panics on coins of different denoms
+			total := SumOutputs(transaction.Outputs)
+			if !total.DenomsSubsetOf(transaction.Coins) || !total.IsEqual(transaction.Coins) {
+				return fmt.Errorf("transaction %s coins do not match its outputs", transaction.UUID)
+			}
+		}
+		if _, err := TransactionStatusFromString(transaction.Status.String()); err != nil {
+			return fmt.Errorf("transaction %s: %s", transaction.UUID, err.Error())
+		}
+		if len(transaction.Signatures) != len(wallet.PubKeys) {
+			return fmt.Errorf("transaction %s does not have a signature slot per wallet pubkey", transaction.UUID)
+		}
+	}
+
+	counts := make(map[string]bool)
+	for _, count := range data.TransactionCounts {
+		if _, ok := wallets[count.Address.String()]; !ok {
+			return fmt.Errorf("transaction count references unknown wallet %s", count.Address)
+		}
+		if counts[count.Address.String()] {
+			return fmt.Errorf("duplicate transaction count for wallet %s", count.Address)
+		}
+		counts[count.Address.String()] = true
+	}
+
+	for _, update := range data.WalletUpdates {
+		wallet, ok := wallets[update.Wallet.String()]
+		if !ok {
+			return fmt.Errorf("wallet update references unknown wallet %s", update.Wallet)
+		}
+		if !wallet.Successor.Empty() {
+			return fmt.Errorf("wallet update references replaced wallet %s", update.Wallet)
+		}
+		expected, err := NewMultiSigWallet(update.Name, update.PubKeys, update.MinSigTx)
+		if err != nil {
+			return fmt.Errorf("invalid wallet update of %s: %s", update.Wallet, err.Error())
+		}
+		if !expected.Address.Equals(update.Successor) {
+			return fmt.Errorf("invalid wallet update of %s: successor does not match pubkeys (%s)", update.Wallet, expected.Address)
+		}
+		for _, approval := range update.Approvals {
+			if !wallet.IsMember(approval) {
+				return fmt.Errorf("wallet update of %s approved by non member %s", update.Wallet, approval)
+			}
+		}
+	}
 	return nil
 }
 
 func DefaultGenesisState() GenesisState {
-	return GenesisState{}
+	return GenesisState{
+		Params: DefaultParams(),
+	}
 }
 
 func InitGenesis(ctx sdk.Context, keeper Keeper, data GenesisState) []abci.ValidatorUpdate {
+	keeper.SetParams(ctx, data.Params)
+	keeper.SetSchemaVersion(ctx, SchemaVersion)
+	for _, wallet := range data.Wallets {
+		keeper.SetWallet(ctx, wallet)
+	}
+	for _, transaction := range data.Transactions {
+		keeper.SetTransaction(ctx, transaction)
+	}
+	for _, count := range data.TransactionCounts {
+		keeper.SetTransactionCount(ctx, count.Address, count.Count)
+	}
+	for _, update := range data.WalletUpdates {
+		keeper.SetWalletUpdate(ctx, update)
+	}
 	return []abci.ValidatorUpdate{}
 }
 
 func ExportGenesis(ctx sdk.Context, k Keeper) GenesisState {
-	return GenesisState{}
+	wallets := k.GetAllWallets(ctx)
+	var counts []TransactionCount
+	for _, wallet := range wallets {
+		count := k.GetTransactionCount(ctx, wallet.Address)
+		if count > 0 {
+			counts = append(counts, TransactionCount{Address: wallet.Address, Count: count})
+		}
+	}
+	return NewGenesisState(k.GetParams(ctx), wallets, k.GetAllTransactions(ctx), counts, k.GetAllWalletUpdates(ctx))
+}
+
+// PrepForZeroHeightGenesis rebases the creation height of every transaction
+// on the current height, so their age is kept when the chain restarts at
+// height zero.
+func PrepForZeroHeightGenesis(ctx sdk.Context, k Keeper) {
+	height := ctx.BlockHeight()
+	for _, transaction := range k.GetAllTransactions(ctx) {
+		transaction.CreatedAt -= height
+		k.SetTransaction(ctx, transaction)
+	}
+	for _, update := range k.GetAllWalletUpdates(ctx) {
+		update.CreatedAt -= height
+		k.SetWalletUpdate(ctx, update)
+	}
 }
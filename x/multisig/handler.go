@@ -3,7 +3,9 @@ package multisig
 import (
 	"fmt"
 
+	"github.com/cbarraford/cosmos-multisig/x/multisig/tags"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
 )
 
 // NewHandler returns a handler for "multisig" type messages.
@@ -14,10 +16,20 @@ func NewHandler(keeper Keeper) sdk.Handler {
 			return handleMsgCreateWallet(ctx, keeper, msg)
 		case MsgCreateTransaction:
 			return handleMsgCreateTransaction(ctx, keeper, msg)
+		case MsgCreateMultiSendTransaction:
+			return handleMsgCreateMultiSendTransaction(ctx, keeper, msg)
+		case MsgCreateMsgsTransaction:
+			return handleMsgCreateMsgsTransaction(ctx, keeper, msg)
 		case MsgSignTransaction:
 			return handleMsgSignTransaction(ctx, keeper, msg)
 		case MsgCompleteTransaction:
 			return handleMsgCompleteTransaction(ctx, keeper, msg)
+		case MsgCancelTransaction:
+			return handleMsgCancelTransaction(ctx, keeper, msg)
+		case MsgRejectTransaction:
+			return handleMsgRejectTransaction(ctx, keeper, msg)
+		case MsgProposeWalletUpdate:
+			return handleMsgProposeWalletUpdate(ctx, keeper, msg)
 		default:
 			errMsg := fmt.Sprintf("Unrecognized multisig Msg type: %v", msg.Type())
 			return sdk.ErrUnknownRequest(errMsg).Result()
@@ -28,48 +40,226 @@ func NewHandler(keeper Keeper) sdk.Handler {
 // Handle a message to create wallet
 func handleMsgCreateWallet(ctx sdk.Context, keeper Keeper, msg MsgCreateWallet) sdk.Result {
 	var err error
+	params := keeper.GetParams(ctx)
+	if int64(len(msg.PubKeys)) > params.MaxPubKeys {
+		return ErrInvalidWallet(keeper.Codespace(),
+			fmt.Sprintf("Wallets cannot have more than %d public keys", params.MaxPubKeys),
+		).Result()
+	}
 	// check the wallet does not already exist
 	wallet, err := NewMultiSigWallet(msg.Name, msg.PubKeys, msg.MinSigTx)
 	if err != nil {
-		return sdk.ErrUnknownRequest(
+		return ErrInvalidWallet(keeper.Codespace(),
 			fmt.Sprintf("Error creating new wallet: %s", err.Error()),
 		).Result()
 	}
 	current := keeper.GetWallet(ctx, wallet.Address.String())
 	if !current.Address.Empty() {
-		return sdk.ErrUnauthorized("Wallet already exists").Result()
+		return ErrWalletExists(keeper.Codespace()).Result()
 	}
 	keeper.SetWallet(ctx, wallet)
-	return sdk.Result{}
+	return sdk.Result{
+		Tags: sdk.NewTags(
+			tags.Category, tags.TxCategory,
+			tags.Wallet, wallet.Address.String(),
+		).AppendTags(signerTags(msg.Signers)),
+	}
 }
 
 // Handle a message to create transaction
 func handleMsgCreateTransaction(ctx sdk.Context, keeper Keeper, msg MsgCreateTransaction) sdk.Result {
+	wallet, sdkErr := checkTransactionRequest(ctx, keeper, msg.From, msg.Signers, msg.Coins, msg.Memo)
+	if sdkErr != nil {
+		return sdkErr.Result()
+	}
+	if sdkErr := checkBalance(ctx, keeper, msg.From, msg.Coins, msg.Fee); sdkErr != nil {
+		return sdkErr.Result()
+	}
+	uid := keeper.GetNextTransactionID(ctx, msg.From)
 	// check the transaction does not already exist
-	transaction := keeper.GetTransaction(ctx, msg.UUID)
-	if !transaction.From.Empty() {
-		return sdk.ErrUnauthorized("Transaction already exists").Result()
+	if !keeper.GetTransaction(ctx, uid).From.Empty() {
+		return ErrTxExists(keeper.Codespace()).Result()
 	}
-	wallet := keeper.GetWallet(ctx, msg.From.String())
-	if wallet.Address.Empty() {
-		return sdk.ErrUnauthorized("No registered multi-signature wallet for 'from' address").Result()
+	transaction := NewTransaction(
+		uid,
+		requestCreator(msg.Signers),
+		msg.From,
+		msg.To,
+		msg.Coins,
+		ctx.BlockHeight(),
+		signatureSlots(wallet),
+	)
+	return saveTransactionRequest(ctx, keeper, transaction, msg.Memo, msg.Fee, msg.Signers)
+}
+
+// Handle a message to create a transaction to several recipients
+func handleMsgCreateMultiSendTransaction(ctx sdk.Context, keeper Keeper, msg MsgCreateMultiSendTransaction) sdk.Result {
+	total := SumOutputs(msg.Outputs)
+	wallet, sdkErr := checkTransactionRequest(ctx, keeper, msg.From, msg.Signers, total, msg.Memo)
+	if sdkErr != nil {
+		return sdkErr.Result()
 	}
-	sigs := make([]Signature, len(wallet.PubKeys))
-	for i, pubkey := range wallet.PubKeys {
-		sigs[i].PubKey = pubkey
+	if sdkErr := checkBalance(ctx, keeper, msg.From, total, msg.Fee); sdkErr != nil {
+		return sdkErr.Result()
+	}
+	uid := keeper.GetNextTransactionID(ctx, msg.From)
+	// check the transaction does not already exist
+	if !keeper.GetTransaction(ctx, uid).From.Empty() {
+		return ErrTxExists(keeper.Codespace()).Result()
 	}
-	coins := sdk.NewCoins(
-		sdk.NewCoin(msg.Denom, msg.Amount),
+	transaction := NewMultiSendTransaction(
+		uid,
+		requestCreator(msg.Signers),
+		msg.From,
+		msg.Outputs,
+		ctx.BlockHeight(),
+		signatureSlots(wallet),
 	)
-	transaction = NewTransaction(
+	return saveTransactionRequest(ctx, keeper, transaction, msg.Memo, msg.Fee, msg.Signers)
+}
+
+// Handle a message to create a transaction of msgs of other modules
+func handleMsgCreateMsgsTransaction(ctx sdk.Context, keeper Keeper, msg MsgCreateMsgsTransaction) sdk.Result {
+	// the coins sent by bank msgs are checked as those of other requests
+	total := SumMsgsCoins(msg.Msgs)
+	wallet, sdkErr := checkTransactionRequest(ctx, keeper, msg.From, msg.Signers, total, msg.Memo)
+	if sdkErr != nil {
+		return sdkErr.Result()
+	}
+	if sdkErr := checkBalance(ctx, keeper, msg.From, total, msg.Fee); sdkErr != nil {
+		return sdkErr.Result()
+	}
+	uid := keeper.GetNextTransactionID(ctx, msg.From)
+	// check the transaction does not already exist
+	if !keeper.GetTransaction(ctx, uid).From.Empty() {
+		return ErrTxExists(keeper.Codespace()).Result()
+	}
+	transaction := NewMsgsTransaction(
+		uid,
+		requestCreator(msg.Signers),
 		msg.From,
-		msg.To,
-		coins,
+		msg.Msgs,
 		ctx.BlockHeight(),
-		sigs,
+		signatureSlots(wallet),
 	)
+	return saveTransactionRequest(ctx, keeper, transaction, msg.Memo, msg.Fee, msg.Signers)
+}
+
+// checks every signer of a message is a member of the wallet
+func checkMembers(keeper Keeper, wallet MultiSigWallet, signers []sdk.AccAddress) sdk.Error {
+	for _, signer := range signers {
+		if !wallet.IsMember(signer) {
+			return ErrNotMember(keeper.Codespace(), signer)
+		}
+	}
+	return nil
+}
+
+// sets the memo and fee of a new transaction request, pins it to the current
+// account number and sequence of the wallet and saves it
+func saveTransactionRequest(ctx sdk.Context, keeper Keeper, transaction Transaction, memo string, fee auth.StdFee, signers []sdk.AccAddress) sdk.Result {
+	transaction.Memo = memo
+	transaction.Fee = requestFee(fee)
+	if err := keeper.PinTransaction(ctx, &transaction); err != nil {
+		return err.Result()
+	}
 	keeper.SetTransaction(ctx, transaction)
-	return sdk.Result{}
+	resTags := transactionTags(transaction, signers)
+	for _, recipient := range transaction.Recipients() {
+		resTags = resTags.AppendTag(tags.Recipient, recipient.String())
+	}
+	return sdk.Result{
+		Data: []byte(transaction.UUID),
+		Tags: resTags,
+	}
+}
+
+// tags of the results of the handlers of transaction requests
+func transactionTags(transaction Transaction, signers []sdk.AccAddress) sdk.Tags {
+	return sdk.NewTags(
+		tags.Category, tags.TxCategory,
+		tags.Wallet, transaction.From.String(),
+		tags.TransactionID, transaction.UUID,
+		tags.Status, string(transaction.Status),
+	).AppendTags(signerTags(signers))
+}
+
+// a signer tag per signer of a message
+func signerTags(signers []sdk.AccAddress) sdk.Tags {
+	resTags := sdk.EmptyTags()
+	for _, signer := range signers {
+		resTags = resTags.AppendTag(tags.Signer, signer.String())
+	}
+	return resTags
+}
+
+// checks a new transaction request sending coins out of a wallet is created
+// by wallet members and allowed by the module params, and returns the wallet
+func checkTransactionRequest(ctx sdk.Context, keeper Keeper, from sdk.AccAddress, signers []sdk.AccAddress, coins sdk.Coins, memo string) (MultiSigWallet, sdk.Error) {
+	wallet := keeper.GetWallet(ctx, from.String())
+	if wallet.Address.Empty() {
+		return wallet, ErrWalletNotFound(keeper.Codespace(), from.String())
+	}
+	if err := checkMembers(keeper, wallet, signers); err != nil {
+		return wallet, err
+	}
+	params := keeper.GetParams(ctx)
+	for _, coin := range coins {
+		if !params.IsAllowedDenom(coin.Denom) {
+			return wallet, ErrDenomNotAllowed(keeper.Codespace(), coin.Denom)
+		}
+	}
+	if keeper.CountPendingTransactions(ctx, from) >= params.MaxPendingTransactions {
+		return wallet, ErrTooManyPending(keeper.Codespace(), params.MaxPendingTransactions)
+	}
+	// the memo must fit in the final multisig transaction
+	maxMemo := keeper.accountKeeper.GetParams(ctx).MaxMemoCharacters
+	if uint64(len(memo)) > maxMemo {
+		return wallet, sdk.ErrMemoTooLarge(
+			fmt.Sprintf("Memo is %d characters long, the maximum is %d", len(memo), maxMemo),
+		)
+	}
+	return wallet, nil
+}
+
+// checks the balance of a wallet covers the coins sent by a new transaction
+// request plus its fee
+func checkBalance(ctx sdk.Context, keeper Keeper, from sdk.AccAddress, coins sdk.Coins, fee auth.StdFee) sdk.Error {
+	balance := keeper.coinKeeper.GetCoins(ctx, from)
+	if !balance.IsAllGTE(coins.Add(fee.Amount)) {
+		return sdk.ErrInsufficientCoins(
+			fmt.Sprintf("Wallet balance %s is lower than the total %s plus fee %s", balance, coins, fee.Amount),
+		)
+	}
+	return nil
+}
+
+// a zero gas stands for the default gas of multisig transactions
+func requestFee(fee auth.StdFee) auth.StdFee {
+	if fee.Gas == 0 {
+		fee.Gas = DefaultGas
+	}
+	if fee.Amount == nil {
+		fee.Amount = sdk.Coins{}
+	}
+	return fee
+}
+
+// returns an empty signature per pubkey of the wallet, in order
+func signatureSlots(wallet MultiSigWallet) []Signature {
+	sigs := make([]Signature, len(wallet.PubKeys))
+	for i, pubkey := range wallet.PubKeys {
+		sigs[i].PubKey = pubkey
+	}
+	return sigs
+}
+
+// the first signer of a message creating a transaction request is its creator
+func requestCreator(signers []sdk.AccAddress) sdk.AccAddress {
+	if len(signers) > 0 {
+		return signers[0]
+	}
+	return nil
 }
 
 // Handle a message to sign transaction
@@ -77,25 +267,252 @@ func handleMsgSignTransaction(ctx sdk.Context, keeper Keeper, msg MsgSignTransac
 	var err error
 	transaction := keeper.GetTransaction(ctx, msg.UUID)
 	if transaction.From.Empty() {
-		return sdk.ErrUnauthorized("No transaction found.").Result()
+		return ErrTxNotFound(keeper.Codespace()).Result()
+	}
+	if err := checkSignable(ctx, keeper, transaction); err != nil {
+		return err.Result()
+	}
+	wallet := keeper.GetWallet(ctx, transaction.From.String())
+	if err := checkMembers(keeper, wallet, msg.Signers); err != nil {
+		return err.Result()
+	}
+	// signatures made for a previous sequence of the wallet are removed
+	keeper.RefreshTransaction(ctx, &transaction)
+	signBytes := keeper.GetSignBytes(ctx, transaction)
+	sig := Signature{
+		PubKey:       msg.PubKey,
+		PubKeyBase64: msg.PubKeyBase64,
+		Signature:    msg.Signature,
+	}
+	signer, err := PubKeyAddress(msg.PubKey)
+	if err != nil {
+		return sdk.ErrInvalidPubKey(err.Error()).Result()
+	}
+	if transaction.HasRejected(signer) {
+		return ErrConflictingVote(keeper.Codespace(), "Member has rejected this transaction").Result()
+	}
+	err = sig.Verify(signBytes)
+	if err != nil {
+		return ErrInvalidSignature(keeper.Codespace(), err.Error()).Result()
 	}
 	err = transaction.AddSignature(msg.PubKey, msg.PubKeyBase64, msg.Signature)
 	if err != nil {
-		return sdk.ErrUnauthorized(
+		return ErrInvalidSignature(keeper.Codespace(),
 			fmt.Sprintf("Failed to sign transaction: %s", err.Error()),
 		).Result()
 	}
+	resTags := transactionTags(transaction, msg.Signers)
+	if transaction.Status == StatusPending && transaction.CountSignatures() >= wallet.MinSigTx {
+		err = transaction.SetStatus(StatusReadyToBroadcast)
+		if err != nil {
+			return ErrInvalidStatus(keeper.Codespace(), err.Error()).Result()
+		}
+		resTags = transactionTags(transaction, msg.Signers).AppendTag(tags.ThresholdReached, "true")
+	}
 	keeper.SetTransaction(ctx, transaction)
-	return sdk.Result{}
+	return sdk.Result{Tags: resTags}
+}
+
+// checks signatures can still be added to a transaction request. The sequence
+// of a stale ready request may have been used by its own broadcast, so it is
+// completed or cancelled rather than signed again. Ready requests overtaken by
+// another request are repinned when that one is completed.
+func checkSignable(ctx sdk.Context, keeper Keeper, transaction Transaction) sdk.Error {
+	if !transaction.Status.IsSignable() {
+		return ErrInvalidStatus(keeper.Codespace(),
+			fmt.Sprintf("Transaction can no longer be signed (%s)", transaction.Status),
+		)
+	}
+	if transaction.Status == StatusReadyToBroadcast && keeper.IsStale(ctx, transaction) {
+		return ErrInvalidStatus(keeper.Codespace(),
+			"Transaction sequence has been used, complete or cancel the transaction",
+		)
+	}
+	return nil
 }
 
 // Handle a message to complete transaction
 func handleMsgCompleteTransaction(ctx sdk.Context, keeper Keeper, msg MsgCompleteTransaction) sdk.Result {
 	transaction := keeper.GetTransaction(ctx, msg.UUID)
 	if transaction.From.Empty() {
-		return sdk.ErrUnauthorized("No transaction found.").Result()
+		return ErrTxNotFound(keeper.Codespace()).Result()
+	}
+	if transaction.Status == StatusCompleted {
+		return ErrAlreadyCompleted(keeper.Codespace(), transaction.TxID).Result()
+	}
+	wallet := keeper.GetWallet(ctx, transaction.From.String())
+	if err := checkMembers(keeper, wallet, msg.Signers); err != nil {
+		return err.Result()
+	}
+	if msg.Height > ctx.BlockHeight() {
+		return ErrInvalidTx(keeper.Codespace(), "Transaction height is in the future").Result()
+	}
+	// the broadcast transaction used the pinned sequence of the wallet
+	acc := keeper.accountKeeper.GetAccount(ctx, transaction.From)
+	if acc == nil || acc.GetSequence() <= transaction.Sequence {
+		return ErrSequenceNotUsed(keeper.Codespace()).Result()
+	}
+	if transaction.Status == StatusPending {
+		return ErrThresholdNotMet(keeper.Codespace(), transaction.Status).Result()
+	}
+	// the multisig transaction was broadcast with the pinned sequence, and
+	// its result completes the request
+	err := transaction.SetStatus(StatusBroadcast)
+	if err == nil {
+		err = transaction.SetStatus(StatusCompleted)
+	}
+	if err != nil {
+		return ErrInvalidStatus(keeper.Codespace(),
+			fmt.Sprintf("Failed to complete transaction: %s", err.Error()),
+		).Result()
 	}
 	transaction.TxID = msg.TxID
+	transaction.TxHeight = msg.Height
+	transaction.TxGasUsed = msg.GasUsed
+	transaction.TxCode = msg.Code
+	keeper.SetTransaction(ctx, transaction)
+	// the broadcast transaction used the sequence of the wallet, so the
+	// signatures of its other transactions are stale
+	keeper.RefreshWalletTransactions(ctx, transaction.From)
+	return sdk.Result{Tags: transactionTags(transaction, msg.Signers)}
+}
+
+// Handle a message to cancel transaction
+func handleMsgCancelTransaction(ctx sdk.Context, keeper Keeper, msg MsgCancelTransaction) sdk.Result {
+	transaction := keeper.GetTransaction(ctx, msg.UUID)
+	if transaction.From.Empty() {
+		return ErrTxNotFound(keeper.Codespace()).Result()
+	}
+	if !transaction.Status.CanTransitionTo(StatusCancelled) {
+		return ErrInvalidStatus(keeper.Codespace(),
+			fmt.Sprintf("Transaction can no longer be cancelled (%s)", transaction.Status),
+		).Result()
+	}
+	wallet := keeper.GetWallet(ctx, transaction.From.String())
+	// the creator may withdraw their own request, otherwise a threshold of
+	// members must agree to cancel it
+	cancel := false
+	for _, signer := range msg.Signers {
+		if signer.Equals(transaction.Creator) {
+			cancel = true
+			continue
+		}
+		if !wallet.IsMember(signer) {
+			return ErrNotMember(keeper.Codespace(), signer).Result()
+		}
+		transaction.AddCancellation(signer)
+	}
+	if cancel || len(transaction.Cancellations) >= wallet.MinSigTx {
+		err := transaction.SetStatus(StatusCancelled)
+		if err != nil {
+			return ErrInvalidStatus(keeper.Codespace(), err.Error()).Result()
+		}
+	}
+	keeper.SetTransaction(ctx, transaction)
+	return sdk.Result{Tags: transactionTags(transaction, msg.Signers)}
+}
+
+// Handle a message to reject transaction
+func handleMsgRejectTransaction(ctx sdk.Context, keeper Keeper, msg MsgRejectTransaction) sdk.Result {
+	transaction := keeper.GetTransaction(ctx, msg.UUID)
+	if transaction.From.Empty() {
+		return ErrTxNotFound(keeper.Codespace()).Result()
+	}
+	if transaction.Status != StatusPending {
+		return ErrInvalidStatus(keeper.Codespace(),
+			fmt.Sprintf("Transaction can no longer be rejected (%s)", transaction.Status),
+		).Result()
+	}
+	wallet := keeper.GetWallet(ctx, transaction.From.String())
+	for _, signer := range msg.Signers {
+		pubkey, ok := wallet.GetMemberPubKey(signer)
+		if !ok {
+			return ErrNotMember(keeper.Codespace(), signer).Result()
+		}
+		if transaction.HasSigned(pubkey) {
+			return ErrConflictingVote(keeper.Codespace(),
+				fmt.Sprintf("%s has already signed the transaction", signer),
+			).Result()
+		}
+		transaction.AddRejection(signer)
+	}
+	// reject once the remaining members can no longer reach the threshold
+	if len(wallet.PubKeys)-len(transaction.Rejections) < wallet.MinSigTx {
+		err := transaction.SetStatus(StatusRejected)
+		if err != nil {
+			return ErrInvalidStatus(keeper.Codespace(), err.Error()).Result()
+		}
+	}
 	keeper.SetTransaction(ctx, transaction)
-	return sdk.Result{}
+	return sdk.Result{Tags: transactionTags(transaction, msg.Signers)}
+}
+
+// Handle a message to propose, or approve, a wallet update
+func handleMsgProposeWalletUpdate(ctx sdk.Context, keeper Keeper, msg MsgProposeWalletUpdate) sdk.Result {
+	wallet := keeper.GetWallet(ctx, msg.Wallet.String())
+	if wallet.Address.Empty() {
+		return ErrWalletNotFound(keeper.Codespace(), msg.Wallet.String()).Result()
+	}
+	if !wallet.Successor.Empty() {
+		return ErrWalletReplaced(keeper.Codespace(), wallet.Successor).Result()
+	}
+	params := keeper.GetParams(ctx)
+	if int64(len(msg.PubKeys)) > params.MaxPubKeys {
+		return ErrInvalidWallet(keeper.Codespace(),
+			fmt.Sprintf("Wallets cannot have more than %d public keys", params.MaxPubKeys),
+		).Result()
+	}
+	successor, err := NewMultiSigWallet(msg.Name, msg.PubKeys, msg.MinSigTx)
+	if err != nil {
+		return ErrInvalidWallet(keeper.Codespace(),
+			fmt.Sprintf("Error creating new wallet: %s", err.Error()),
+		).Result()
+	}
+	if successor.Address.Equals(wallet.Address) {
+		return ErrInvalidWalletUpdate(keeper.Codespace(), "Wallet update does not change the public keys or threshold").Result()
+	}
+
+	// proposing the same pubkeys and threshold approves the existing proposal
+	update := keeper.GetWalletUpdate(ctx, wallet.Address, successor.Address)
+	if update.Wallet.Empty() {
+		update = NewWalletUpdate(wallet.Address, successor, requestFee(msg.Fee), ctx.BlockHeight())
+	}
+	if err := checkMembers(keeper, wallet, msg.Signers); err != nil {
+		return err.Result()
+	}
+	for _, signer := range msg.Signers {
+		update.AddApproval(signer)
+	}
+
+	resTags := sdk.NewTags(
+		tags.Category, tags.TxCategory,
+		tags.Wallet, wallet.Address.String(),
+		tags.Successor, successor.Address.String(),
+	).AppendTags(signerTags(msg.Signers))
+	if len(update.Approvals) < wallet.MinSigTx {
+		keeper.SetWalletUpdate(ctx, update)
+		return sdk.Result{Tags: resTags}
+	}
+
+	// the sweep is checked as any other transaction request, before the
+	// wallet is replaced
+	sweep, sdkErr := keeper.SweepCoins(ctx, wallet.Address, update.Fee)
+	if sdkErr != nil {
+		return sdkErr.Result()
+	}
+	if !sweep.IsZero() {
+		if _, sdkErr := checkTransactionRequest(ctx, keeper, wallet.Address, msg.Signers, sweep, ""); sdkErr != nil {
+			return sdkErr.Result()
+		}
+	}
+
+	resTags = resTags.AppendTag(tags.ThresholdReached, "true")
+	uid := keeper.ExecuteWalletUpdate(ctx, wallet, update, sweep)
+	if uid == "" {
+		return sdk.Result{Tags: resTags}
+	}
+	return sdk.Result{
+		Data: []byte(uid),
+		Tags: resTags.AppendTag(tags.TransactionID, uid),
+	}
 }
@@ -0,0 +1,145 @@
+package multisig
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// invariant routes of the multisig module
+const (
+	RouteRegisteredWallets = "registered-wallets"
+	RouteSignatureSlots    = "signature-slots"
+	RouteWalletAddresses   = "wallet-addresses"
+	RouteIndexes           = "indexes"
+)
+
+// an invariant and its route
+type invariantRoute struct {
+	route string
+	invar sdk.Invariant
+}
+
+// the multisig invariants, in the order they are checked
+func invariantRoutes(k Keeper) []invariantRoute {
+	return []invariantRoute{
+		{RouteRegisteredWallets, RegisteredWalletsInvariant(k)},
+		{RouteSignatureSlots, SignatureSlotsInvariant(k)},
+		{RouteWalletAddresses, WalletAddressesInvariant(k)},
+		{RouteIndexes, IndexesInvariant(k)},
+	}
+}
+
+// RegisterInvariants registers all multisig invariants
+func RegisterInvariants(ir sdk.InvariantRegistry, k Keeper) {
+	for _, r := range invariantRoutes(k) {
+		ir.RegisterRoute(ModuleName, r.route, r.invar)
+	}
+}
+
+// RegisteredWalletsInvariant checks every transaction is sent from a
+// registered wallet, and every wallet successor is registered
+func RegisteredWalletsInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) error {
+		for _, wallet := range k.GetAllWallets(ctx) {
+			if !wallet.Successor.Empty() && k.GetWallet(ctx, wallet.Successor.String()).Address.Empty() {
+				return fmt.Errorf("successor %s of wallet %s is not registered", wallet.Successor, wallet.Address)
+			}
+		}
+		for _, transaction := range k.GetAllTransactions(ctx) {
+			if k.GetWallet(ctx, transaction.From.String()).Address.Empty() {
+				return fmt.Errorf("wallet %s of transaction %s is not registered", transaction.From, transaction.UUID)
+			}
+		}
+		return nil
+	}
+}
+
+// SignatureSlotsInvariant checks the signatures of every transaction match
+// the pubkeys of its wallet, in order
+func SignatureSlotsInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) error {
+		for _, transaction := range k.GetAllTransactions(ctx) {
+			wallet := k.GetWallet(ctx, transaction.From.String())
+			if len(transaction.Signatures) != len(wallet.PubKeys) {
+				return fmt.Errorf("transaction %s has %d signatures, its wallet %d pubkeys",
+					transaction.UUID, len(transaction.Signatures), len(wallet.PubKeys))
+			}
+			for i, sig := range transaction.Signatures {
+				if sig.PubKey != wallet.PubKeys[i] {
+					return fmt.Errorf("signature %d of transaction %s is for %s, not %s",
+						i, transaction.UUID, sig.PubKey, wallet.PubKeys[i])
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// WalletAddressesInvariant checks the address of every wallet is the address
+// of its pubkeys and threshold
+func WalletAddressesInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) error {
+		for _, wallet := range k.GetAllWallets(ctx) {
+			expected, err := NewMultiSigWallet(wallet.Name, wallet.PubKeys, wallet.MinSigTx)
+			if err != nil {
+				return fmt.Errorf("wallet %s is invalid: %s", wallet.Address, err)
+			}
+			if !expected.Address.Equals(wallet.Address) {
+				return fmt.Errorf("wallet %s has the pubkeys and threshold of %s", wallet.Address, expected.Address)
+			}
+		}
+		return nil
+	}
+}
+
+// IndexesInvariant checks the index keys are exactly the keys derived from
+// the wallet and transaction records
+func IndexesInvariant(k Keeper) sdk.Invariant {
+	return func(ctx sdk.Context) error {
+		expected := make(map[string][]byte)
+		for _, wallet := range k.GetAllWallets(ctx) {
+			for _, pubkey := range wallet.PubKeys {
+				expected[string(WalletPubKeyKey(pubkey, wallet.Address))] = wallet.Address
+			}
+		}
+		for _, transaction := range k.GetAllTransactions(ctx) {
+			uid := []byte(transaction.UUID)
+			expected[string(WalletTransactionKey(transaction.From, transaction.CreatedAt, transaction.UUID))] = uid
+			for _, recipient := range transaction.Recipients() {
+				expected[string(RecipientTransactionKey(recipient, transaction.CreatedAt, transaction.UUID))] = uid
+			}
+			if transaction.Status.CanTransitionTo(StatusExpired) {
+				expected[string(ExpiryQueueKey(transaction.CreatedAt, transaction.UUID))] = uid
+				expected[string(OpenTransactionKey(transaction.From, transaction.UUID))] = uid
+			}
+		}
+
+		store := ctx.KVStore(k.storeKey)
+		for _, prefix := range IndexKeyPrefixes {
+			iterator := sdk.KVStorePrefixIterator(store, prefix)
+			for ; iterator.Valid(); iterator.Next() {
+				key := string(iterator.Key())
+				value, ok := expected[key]
+				if !ok || !bytes.Equal(value, iterator.Value()) {
+					iterator.Close()
+					return fmt.Errorf("index key %X does not match a record", iterator.Key())
+				}
+				delete(expected, key)
+			}
+			iterator.Close()
+		}
+
+		missing := make([]string, 0, len(expected))
+		for key := range expected {
+			missing = append(missing, key)
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			return fmt.Errorf("index key %X is missing", missing[0])
+		}
+		return nil
+	}
+}
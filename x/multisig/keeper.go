@@ -2,10 +2,12 @@ package multisig
 
 import (
 	"fmt"
-	"strings"
 
+	"github.com/cbarraford/cosmos-multisig/x/multisig/tags"
 	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/x/auth"
 	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/cosmos-sdk/x/params"
 	"github.com/google/uuid"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -13,29 +15,74 @@ import (
 
 // Keeper maintains the link to data storage and exposes getter/setter methods for the various parts of the state machine
 type Keeper struct {
-	coinKeeper bank.Keeper
+	coinKeeper    bank.Keeper
+	accountKeeper auth.AccountKeeper
 
 	storeKey sdk.StoreKey // Unexposed key to access store from sdk.Context
 
+	paramspace params.Subspace
+
 	cdc *codec.Codec // The wire codec for binary encoding/decoding.
+
+	codespace sdk.CodespaceType
 }
 
 // NewKeeper creates new instances of the multisig Keeper
-func NewKeeper(coinKeeper bank.Keeper, storeKey sdk.StoreKey, cdc *codec.Codec) Keeper {
+func NewKeeper(coinKeeper bank.Keeper, accountKeeper auth.AccountKeeper, storeKey sdk.StoreKey, paramspace params.Subspace, cdc *codec.Codec, codespace sdk.CodespaceType) Keeper {
 	return Keeper{
-		coinKeeper: coinKeeper,
-		storeKey:   storeKey,
-		cdc:        cdc,
+		coinKeeper:    coinKeeper,
+		accountKeeper: accountKeeper,
+		storeKey:      storeKey,
+		paramspace:    paramspace.WithKeyTable(ParamKeyTable()),
+		cdc:           cdc,
+		codespace:     codespace,
 	}
 }
 
+// Codespace returns the codespace of the multisig errors
+func (k Keeper) Codespace() sdk.CodespaceType {
+	return k.codespace
+}
+
+// GetParams returns the total set of multisig parameters.
+func (k Keeper) GetParams(ctx sdk.Context) (params Params) {
+	k.paramspace.GetParamSet(ctx, &params)
+	return params
+}
+
+// SetParams sets the total set of multisig parameters.
+func (k Keeper) SetParams(ctx sdk.Context, params Params) {
+	k.paramspace.SetParamSet(ctx, &params)
+}
+
+// Returns the version of the store layout. Stores written before the version
+// was stored have the first layout.
+func (k Keeper) GetSchemaVersion(ctx sdk.Context) uint64 {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(SchemaVersionKey)
+	if bz == nil {
+		return 1
+	}
+	var version uint64
+	k.cdc.MustUnmarshalBinaryBare(bz, &version)
+	return version
+}
+
+func (k Keeper) SetSchemaVersion(ctx sdk.Context, version uint64) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(SchemaVersionKey, k.cdc.MustMarshalBinaryBare(version))
+}
+
 func (k Keeper) GetWallet(ctx sdk.Context, address string) MultiSigWallet {
-	address = fmt.Sprintf("wallet-%s", address)
+	addr, err := sdk.AccAddressFromBech32(address)
+	if err != nil || addr.Empty() {
+		return MultiSigWallet{}
+	}
 	store := ctx.KVStore(k.storeKey)
-	if !store.Has([]byte(address)) {
+	bz := store.Get(WalletKey(addr))
+	if bz == nil {
 		return MultiSigWallet{}
 	}
-	bz := store.Get([]byte(address))
 	var wallet MultiSigWallet
 	k.cdc.MustUnmarshalBinaryBare(bz, &wallet)
 	return wallet
@@ -43,27 +90,356 @@ func (k Keeper) GetWallet(ctx sdk.Context, address string) MultiSigWallet {
 
 // Sets the entire wallet metadata struct for a multisig wallet
 func (k Keeper) SetWallet(ctx sdk.Context, wallet MultiSigWallet) {
-	address := fmt.Sprintf("wallet-%s", wallet.Address.String())
 	store := ctx.KVStore(k.storeKey)
-	store.Set([]byte(address), k.cdc.MustMarshalBinaryBare(wallet))
+	k.deleteWalletIndexes(ctx, k.GetWallet(ctx, wallet.Address.String()))
+	store.Set(WalletKey(wallet.Address), k.cdc.MustMarshalBinaryBare(wallet))
+	for _, pubkey := range wallet.PubKeys {
+		store.Set(WalletPubKeyKey(pubkey, wallet.Address), wallet.Address)
+	}
+}
+
+func (k Keeper) deleteWalletIndexes(ctx sdk.Context, wallet MultiSigWallet) {
+	store := ctx.KVStore(k.storeKey)
+	for _, pubkey := range wallet.PubKeys {
+		store.Delete(WalletPubKeyKey(pubkey, wallet.Address))
+	}
+}
+
+// Returns the wallets a pubkey is a member of
+func (k Keeper) GetWalletsByPubKey(ctx sdk.Context, pubkey string) []MultiSigWallet {
+	var wallets []MultiSigWallet
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, PubKeyWalletsKey(pubkey))
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		address := sdk.AccAddress(iterator.Value())
+		wallets = append(wallets, k.GetWallet(ctx, address.String()))
+	}
+	return wallets
+}
+
+// Returns the proposal to replace a wallet by a successor wallet
+func (k Keeper) GetWalletUpdate(ctx sdk.Context, wallet, successor sdk.AccAddress) WalletUpdate {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(WalletUpdateKey(wallet, successor))
+	if bz == nil {
+		return WalletUpdate{}
+	}
+	var update WalletUpdate
+	k.cdc.MustUnmarshalBinaryBare(bz, &update)
+	return update
+}
+
+func (k Keeper) SetWalletUpdate(ctx sdk.Context, update WalletUpdate) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(WalletUpdateKey(update.Wallet, update.Successor), k.cdc.MustMarshalBinaryBare(update))
+}
+
+func (k Keeper) DeleteWalletUpdate(ctx sdk.Context, wallet, successor sdk.AccAddress) {
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(WalletUpdateKey(wallet, successor))
+}
+
+// Returns the pending update proposals of a wallet
+func (k Keeper) GetWalletUpdates(ctx sdk.Context, wallet sdk.AccAddress) []WalletUpdate {
+	return k.getWalletUpdates(ctx, WalletUpdatesKey(wallet))
+}
+
+// Returns all pending wallet update proposals in the store
+func (k Keeper) GetAllWalletUpdates(ctx sdk.Context) []WalletUpdate {
+	return k.getWalletUpdates(ctx, WalletUpdateKeyPrefix)
+}
+
+func (k Keeper) getWalletUpdates(ctx sdk.Context, prefix []byte) []WalletUpdate {
+	var updates []WalletUpdate
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, prefix)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var update WalletUpdate
+		k.cdc.MustUnmarshalBinaryBare(iterator.Value(), &update)
+		updates = append(updates, update)
+	}
+	return updates
+}
+
+// Returns the coins the sweep transaction of a replaced wallet sends to its
+// successor: the balance of the wallet in allowed denoms, less the fee of the
+// sweep. A wallet without coins has nothing to sweep.
+func (k Keeper) SweepCoins(ctx sdk.Context, address sdk.AccAddress, fee auth.StdFee) (sdk.Coins, sdk.Error) {
+	balance := k.coinKeeper.GetCoins(ctx, address)
+	if balance.IsZero() {
+		return sdk.Coins{}, nil
+	}
+	remaining, negative := balance.SafeSub(fee.Amount)
+	if negative {
+		return nil, sdk.ErrInsufficientCoins(
+			fmt.Sprintf("Wallet balance %s is lower than the sweep fee %s", balance, fee.Amount),
+		)
+	}
+	params := k.GetParams(ctx)
+	coins := sdk.Coins{}
+	for _, coin := range remaining {
+		if params.IsAllowedDenom(coin.Denom) {
+			coins = append(coins, coin)
+		}
+	}
+	return coins, nil
+}
+
+// Replaces a wallet by the successor wallet of an approved update: the
+// successor wallet is created if needed, linked from the old wallet, and a
+// transaction request sweeping coins of the old wallet to the successor, with
+// the fee of the update, is created. Returns the uuid of the sweep
+// transaction, if any.
+func (k Keeper) ExecuteWalletUpdate(ctx sdk.Context, wallet MultiSigWallet, update WalletUpdate, coins sdk.Coins) string {
+	successor := k.GetWallet(ctx, update.Successor.String())
+	if successor.Address.Empty() {
+		successor = MultiSigWallet{
+			Name:     update.Name,
+			MinSigTx: update.MinSigTx,
+			PubKeys:  update.PubKeys,
+			Address:  update.Successor,
+		}
+		k.SetWallet(ctx, successor)
+	}
+
+	wallet.Successor = successor.Address
+	k.SetWallet(ctx, wallet)
+
+	// the wallet is replaced, other proposals are void
+	for _, u := range k.GetWalletUpdates(ctx, wallet.Address) {
+		k.DeleteWalletUpdate(ctx, u.Wallet, u.Successor)
+	}
+
+	if coins.IsZero() {
+		return ""
+	}
+	transaction := NewTransaction(
+		k.GetNextTransactionID(ctx, wallet.Address),
+		nil,
+		wallet.Address,
+		successor.Address,
+		coins,
+		ctx.BlockHeight(),
+		signatureSlots(wallet),
+	)
+	transaction.Fee = update.Fee
+	// the wallet holds coins, so its account exists
+	if err := k.PinTransaction(ctx, &transaction); err != nil {
+		panic(err)
+	}
+	k.SetTransaction(ctx, transaction)
+	return transaction.UUID
+}
+
+// Returns a deterministic uuid for the next transaction of a wallet, derived
+// from the wallet address and the number of transactions created for it
+func (k Keeper) GetNextTransactionID(ctx sdk.Context, address sdk.AccAddress) string {
+	count := k.GetTransactionCount(ctx, address)
+	k.SetTransactionCount(ctx, address, count+1)
+	name := fmt.Sprintf("%s/%d", address.String(), count)
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(name)).String()
+}
+
+// Returns the number of transactions created for a wallet
+func (k Keeper) GetTransactionCount(ctx sdk.Context, address sdk.AccAddress) uint64 {
+	store := ctx.KVStore(k.storeKey)
+	var count uint64
+	if bz := store.Get(TransactionCountKey(address)); bz != nil {
+		k.cdc.MustUnmarshalBinaryBare(bz, &count)
+	}
+	return count
+}
+
+func (k Keeper) SetTransactionCount(ctx sdk.Context, address sdk.AccAddress, count uint64) {
+	store := ctx.KVStore(k.storeKey)
+	store.Set(TransactionCountKey(address), k.cdc.MustMarshalBinaryBare(count))
 }
 
 func (k Keeper) GetTransaction(ctx sdk.Context, uid string) Transaction {
-	key := fmt.Sprintf("transaction-%s", uid)
 	store := ctx.KVStore(k.storeKey)
-	if !store.Has([]byte(key)) {
+	bz := store.Get(TransactionKey(uid))
+	if bz == nil {
 		return Transaction{}
 	}
-	bz := store.Get([]byte(key))
 	var transaction Transaction
 	k.cdc.MustUnmarshalBinaryBare(bz, &transaction)
 	return transaction
 }
 
 func (k Keeper) SetTransaction(ctx sdk.Context, transaction Transaction) {
-	key := fmt.Sprintf("transaction-%s", transaction.UUID)
 	store := ctx.KVStore(k.storeKey)
-	store.Set([]byte(key), k.cdc.MustMarshalBinaryBare(transaction))
+	k.deleteTransactionIndexes(ctx, k.GetTransaction(ctx, transaction.UUID))
+	store.Set(TransactionKey(transaction.UUID), k.cdc.MustMarshalBinaryBare(transaction))
+	uid := []byte(transaction.UUID)
+	store.Set(WalletTransactionKey(transaction.From, transaction.CreatedAt, transaction.UUID), uid)
+	for _, recipient := range transaction.Recipients() {
+		store.Set(RecipientTransactionKey(recipient, transaction.CreatedAt, transaction.UUID), uid)
+	}
+	if transaction.Status.CanTransitionTo(StatusExpired) {
+		store.Set(ExpiryQueueKey(transaction.CreatedAt, transaction.UUID), uid)
+		store.Set(OpenTransactionKey(transaction.From, transaction.UUID), uid)
+	}
+}
+
+func (k Keeper) deleteTransactionIndexes(ctx sdk.Context, transaction Transaction) {
+	if transaction.UUID == "" {
+		return
+	}
+	store := ctx.KVStore(k.storeKey)
+	store.Delete(WalletTransactionKey(transaction.From, transaction.CreatedAt, transaction.UUID))
+	for _, recipient := range transaction.Recipients() {
+		store.Delete(RecipientTransactionKey(recipient, transaction.CreatedAt, transaction.UUID))
+	}
+	store.Delete(ExpiryQueueKey(transaction.CreatedAt, transaction.UUID))
+	store.Delete(OpenTransactionKey(transaction.From, transaction.UUID))
+}
+
+// Returns the transactions of a wallet, ordered by creation height
+func (k Keeper) GetWalletTransactions(ctx sdk.Context, address sdk.AccAddress) []Transaction {
+	return k.getIndexedTransactions(ctx, WalletTransactionsKey(address))
+}
+
+// Iterates over the transactions of a wallet created between two heights
+// (inclusive, zero for no bound) in creation height order, until the
+// callback returns true
+func (k Keeper) IterateWalletTransactions(ctx sdk.Context, address sdk.AccAddress, minHeight, maxHeight int64, descending bool, cb func(Transaction) (stop bool)) {
+	heightKey := func(height int64) []byte { return WalletTransactionsHeightKey(address, height) }
+	k.iterateHeightIndex(ctx, WalletTransactionsKey(address), heightKey, minHeight, maxHeight, descending, cb)
+}
+
+// Iterates over the transactions sending funds to a recipient created between
+// two heights (inclusive, zero for no bound) in creation height order, until
+// the callback returns true
+func (k Keeper) IterateRecipientTransactions(ctx sdk.Context, address sdk.AccAddress, minHeight, maxHeight int64, descending bool, cb func(Transaction) (stop bool)) {
+	heightKey := func(height int64) []byte { return RecipientTransactionsHeightKey(address, height) }
+	k.iterateHeightIndex(ctx, RecipientTransactionsKey(address), heightKey, minHeight, maxHeight, descending, cb)
+}
+
+// iterates over an index of transactions sorted by creation height, whose
+// height keys are built by heightKey
+func (k Keeper) iterateHeightIndex(ctx sdk.Context, prefix []byte, heightKey func(int64) []byte, minHeight, maxHeight int64, descending bool, cb func(Transaction) (stop bool)) {
+	start := prefix
+	if minHeight != 0 {
+		start = heightKey(minHeight)
+	}
+	end := sdk.PrefixEndBytes(prefix)
+	if maxHeight != 0 {
+		end = heightKey(maxHeight + 1)
+	}
+
+	store := ctx.KVStore(k.storeKey)
+	var iterator sdk.Iterator
+	if descending {
+		iterator = store.ReverseIterator(start, end)
+	} else {
+		iterator = store.Iterator(start, end)
+	}
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		if cb(k.GetTransaction(ctx, string(iterator.Value()))) {
+			break
+		}
+	}
+}
+
+// returns the transactions of an index, whose values are transaction uuids
+func (k Keeper) getIndexedTransactions(ctx sdk.Context, prefix []byte) []Transaction {
+	var transactions []Transaction
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, prefix)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		transactions = append(transactions, k.GetTransaction(ctx, string(iterator.Value())))
+	}
+	return transactions
+}
+
+// Returns the bytes wallet members sign for a transaction, based on the
+// account number and sequence pinned on the transaction
+func (k Keeper) GetSignBytes(ctx sdk.Context, transaction Transaction) []byte {
+	return transaction.GetSignBytes(ctx.ChainID())
+}
+
+// Pins a new transaction to the current account number and sequence of its
+// wallet
+func (k Keeper) PinTransaction(ctx sdk.Context, transaction *Transaction) sdk.Error {
+	acc := k.accountKeeper.GetAccount(ctx, transaction.From)
+	if acc == nil {
+		return sdk.ErrUnknownAddress(
+			fmt.Sprintf("account %s does not exist", transaction.From),
+		)
+	}
+	transaction.AccountNumber = acc.GetAccountNumber()
+	transaction.Sequence = acc.GetSequence()
+	return nil
+}
+
+// Returns whether the sequence of a transaction which can still be signed no
+// longer matches its wallet account
+func (k Keeper) IsStale(ctx sdk.Context, transaction Transaction) bool {
+	if !transaction.Status.IsSignable() {
+		return false
+	}
+	acc := k.accountKeeper.GetAccount(ctx, transaction.From)
+	if acc == nil {
+		return false
+	}
+	return transaction.IsStale(acc.GetAccountNumber(), acc.GetSequence())
+}
+
+// Repins a stale transaction to the current account number and sequence of
+// its wallet, invalidating its signatures. Returns whether the transaction
+// was stale.
+func (k Keeper) RefreshTransaction(ctx sdk.Context, transaction *Transaction) bool {
+	if !k.IsStale(ctx, *transaction) {
+		return false
+	}
+	acc := k.accountKeeper.GetAccount(ctx, transaction.From)
+	transaction.Repin(acc.GetAccountNumber(), acc.GetSequence())
+	return true
+}
+
+// Refreshes the stale transactions of a wallet, and returns their uuids. Only
+// open transactions can be stale, so only these are visited.
+func (k Keeper) RefreshWalletTransactions(ctx sdk.Context, address sdk.AccAddress) []string {
+	var uids []string
+	for _, transaction := range k.getIndexedTransactions(ctx, OpenTransactionsKey(address)) {
+		if k.RefreshTransaction(ctx, &transaction) {
+			k.SetTransaction(ctx, transaction)
+			uids = append(uids, transaction.UUID)
+		}
+	}
+	return uids
+}
+
+// Returns all wallets in the store
+func (k Keeper) GetAllWallets(ctx sdk.Context) []MultiSigWallet {
+	var wallets []MultiSigWallet
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, WalletKeyPrefix)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var wallet MultiSigWallet
+		k.cdc.MustUnmarshalBinaryBare(iterator.Value(), &wallet)
+		wallets = append(wallets, wallet)
+	}
+	return wallets
+}
+
+// Returns all transactions in the store
+func (k Keeper) GetAllTransactions(ctx sdk.Context) []Transaction {
+	var transactions []Transaction
+	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, TransactionKeyPrefix)
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		var transaction Transaction
+		k.cdc.MustUnmarshalBinaryBare(iterator.Value(), &transaction)
+		transactions = append(transactions, transaction)
+	}
+	return transactions
 }
 
 func (k Keeper) GetIterator(ctx sdk.Context) sdk.Iterator {
@@ -71,26 +447,53 @@ func (k Keeper) GetIterator(ctx sdk.Context) sdk.Iterator {
 	return sdk.KVStorePrefixIterator(store, nil)
 }
 
-func (k Keeper) DeleteTransaction(ctx sdk.Context, uid uuid.UUID) {
-	key := fmt.Sprintf("transaction-%s", uid)
+func (k Keeper) DeleteTransaction(ctx sdk.Context, uid string) {
 	store := ctx.KVStore(k.storeKey)
-	store.Delete([]byte(key))
+	k.deleteTransactionIndexes(ctx, k.GetTransaction(ctx, uid))
+	store.Delete(TransactionKey(uid))
 }
 
-func (k Keeper) CleanupTransactions(ctx sdk.Context, maxAge int64) {
+// Returns the number of blocks after which open transactions expire
+func (k Keeper) TransactionLifetime(ctx sdk.Context) (res int64) {
+	k.paramspace.Get(ctx, KeyTransactionLifetime, &res)
+	return
+}
 
-	currentHeight := ctx.BlockHeight()
-	iterator := k.GetIterator(ctx)
+// Returns the number of transactions of a wallet that are still open, ie
+// that may still expire
+func (k Keeper) CountPendingTransactions(ctx sdk.Context, address sdk.AccAddress) int64 {
+	var count int64
 	store := ctx.KVStore(k.storeKey)
+	iterator := sdk.KVStorePrefixIterator(store, OpenTransactionsKey(address))
+	defer iterator.Close()
+	for ; iterator.Valid(); iterator.Next() {
+		count++
+	}
+	return count
+}
 
+// Expires the transactions that are still waiting to be completed after
+// maxAge blocks. Only due transactions of the expiry queue are visited.
+func (k Keeper) CleanupTransactions(ctx sdk.Context, maxAge int64) sdk.Tags {
+	resTags := sdk.EmptyTags()
+	// the queue is sorted by creation height, stop at the first transaction
+	// that is not yet due
+	end := ExpiryQueueHeightKey(ctx.BlockHeight() - maxAge)
+	store := ctx.KVStore(k.storeKey)
+	iterator := store.Iterator(ExpiryQueueKeyPrefix, end)
+	var due []string
 	for ; iterator.Valid(); iterator.Next() {
-		if strings.HasPrefix(string(iterator.Key()), "transaction-") {
-			bz := store.Get(iterator.Key())
-			var transaction Transaction
-			k.cdc.MustUnmarshalBinaryBare(bz, &transaction)
-			if currentHeight > (transaction.CreatedAt + maxAge) {
-				store.Delete(iterator.Key())
-			}
+		due = append(due, string(iterator.Value()))
+	}
+	iterator.Close()
+
+	for _, uid := range due {
+		transaction := k.GetTransaction(ctx, uid)
+		if err := transaction.SetStatus(StatusExpired); err != nil {
+			continue
 		}
+		k.SetTransaction(ctx, transaction)
+		resTags = resTags.AppendTag(tags.Expired, uid)
 	}
+	return resTags
 }
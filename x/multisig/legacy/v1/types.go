@@ -0,0 +1,42 @@
+// Package v1 holds the store layout of the multisig module before the schema
+// version was stored. The first release stored its wallets and transactions
+// under string keys, and had no params.
+package v1
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	SchemaVersion uint64 = 1
+
+	// prefixes of the records, followed by a bech32 address or a uuid
+	WalletKeyPrefix      = "wallet-"
+	TransactionKeyPrefix = "transaction-"
+)
+
+// MultiSigWallet is a wallet of the first release, without a successor
+type MultiSigWallet struct {
+	Name     string         `json:"name"`
+	MinSigTx int            `json:"min_sig_tx"`
+	Address  sdk.AccAddress `json:"address"`
+	PubKeys  []string       `json:"pub_keys"`
+}
+
+type Signature struct {
+	PubKey       string `json:"pub_key"`
+	PubKeyBase64 string `json:"pub_key_base64"`
+	Signature    string `json:"signature"`
+}
+
+// Transaction is a transaction request of the first release, which only
+// sends coins to a single recipient and has no status
+type Transaction struct {
+	UUID       string         `json:"uuid"`
+	From       sdk.AccAddress `json:"from_address"`
+	To         sdk.AccAddress `json:"to_address"`
+	Coins      sdk.Coins      `json:"coins"`
+	Signatures []Signature    `json:"signatures"`
+	TxID       string         `json:"tx_id"`
+	CreatedAt  int64          `json:"created_at"`
+}
@@ -0,0 +1,132 @@
+package multisig
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "github.com/cbarraford/cosmos-multisig/x/multisig/legacy/v1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+)
+
+// Migration rewrites the store of the multisig module from a schema version
+// to the next one
+type Migration func(ctx sdk.Context, k Keeper) error
+
+// the migrations, by the schema version they migrate from
+var migrations = map[uint64]Migration{
+	v1.SchemaVersion: MigrateV1ToV2,
+}
+
+// Migrate rewrites the store from its schema version to the version of this
+// module, running the migrations in order
+func Migrate(ctx sdk.Context, k Keeper) error {
+	version := k.GetSchemaVersion(ctx)
+	if version > SchemaVersion {
+		return fmt.Errorf("store schema version %d is newer than the module (%d)", version, SchemaVersion)
+	}
+	for ; version < SchemaVersion; version++ {
+		migration, ok := migrations[version]
+		if !ok {
+			return fmt.Errorf("no migration from store schema version %d", version)
+		}
+		if err := migration(ctx, k); err != nil {
+			return fmt.Errorf("migration from store schema version %d failed: %s", version, err)
+		}
+		k.SetSchemaVersion(ctx, version+1)
+		ctx.Logger().Info(fmt.Sprintf("migrated multisig store to schema version %d", version+1))
+	}
+	return nil
+}
+
+// MigrateV1ToV2 moves the wallets and transactions of the first release from
+// string keys to binary keys, converts them to the current structs and builds
+// their indexes. The first release had no params, they are set to
+// their default.
+func MigrateV1ToV2(ctx sdk.Context, k Keeper) error {
+	store := ctx.KVStore(k.storeKey)
+
+	// the records are collected first, as the store cannot be written while
+	// it is iterated. Binary keys start with a non printable byte.
+	var keys [][]byte
+	var wallets []v1.MultiSigWallet
+	var transactions []v1.Transaction
+	iterator := store.Iterator([]byte{' '}, nil)
+	for ; iterator.Valid(); iterator.Next() {
+		key := string(iterator.Key())
+		switch {
+		case strings.HasPrefix(key, v1.WalletKeyPrefix):
+			var wallet v1.MultiSigWallet
+			k.cdc.MustUnmarshalBinaryBare(iterator.Value(), &wallet)
+			wallets = append(wallets, wallet)
+		case strings.HasPrefix(key, v1.TransactionKeyPrefix):
+			var transaction v1.Transaction
+			k.cdc.MustUnmarshalBinaryBare(iterator.Value(), &transaction)
+			transactions = append(transactions, transaction)
+		default:
+			iterator.Close()
+			return fmt.Errorf("unknown key %q in a version 1 store", key)
+		}
+		keys = append(keys, iterator.Key())
+	}
+	iterator.Close()
+
+	for _, key := range keys {
+		store.Delete(key)
+	}
+	setMissingParams(ctx, k)
+	// wallets are set first, as transactions are pinned to the account of
+	// their wallet
+	for _, wallet := range wallets {
+		k.SetWallet(ctx, migrateV1Wallet(wallet))
+	}
+	for _, transaction := range transactions {
+		k.SetTransaction(ctx, migrateV1Transaction(ctx, k, transaction))
+	}
+	return nil
+}
+
+// sets the params missing from the param store to their default, as the
+// module reads them in every block
+func setMissingParams(ctx sdk.Context, k Keeper) {
+	params := DefaultParams()
+	for _, pair := range params.ParamSetPairs() {
+		if !k.paramspace.Has(ctx, pair.Key) {
+			k.paramspace.Set(ctx, pair.Key, pair.Value)
+		}
+	}
+}
+
+// converts a wallet of the first release, which has no successor
+func migrateV1Wallet(legacy v1.MultiSigWallet) MultiSigWallet {
+	return MultiSigWallet{
+		Name:     legacy.Name,
+		MinSigTx: legacy.MinSigTx,
+		Address:  legacy.Address,
+		PubKeys:  legacy.PubKeys,
+	}
+}
+
+// converts a transaction of the first release. Its status is derived from its
+// signatures, and it is pinned to the current sequence of its wallet.
+func migrateV1Transaction(ctx sdk.Context, k Keeper, legacy v1.Transaction) Transaction {
+	sigs := make([]Signature, len(legacy.Signatures))
+	for i, sig := range legacy.Signatures {
+		sigs[i] = Signature{PubKey: sig.PubKey, PubKeyBase64: sig.PubKeyBase64, Signature: sig.Signature}
+	}
+	transaction := NewTransaction(legacy.UUID, nil, legacy.From, legacy.To, legacy.Coins, legacy.CreatedAt, sigs)
+	transaction.TxID = legacy.TxID
+	transaction.Fee = auth.NewStdFee(DefaultGas, sdk.Coins{})
+
+	wallet := k.GetWallet(ctx, legacy.From.String())
+	switch {
+	case legacy.TxID != "":
+		transaction.Status = StatusCompleted
+	case wallet.MinSigTx > 0 && transaction.CountSignatures() >= wallet.MinSigTx:
+		transaction.Status = StatusReadyToBroadcast
+	}
+	// wallets which never received coins have no account, their transactions
+	// stay pinned to a zero account number and sequence
+	_ = k.PinTransaction(ctx, &transaction)
+	return transaction
+}
@@ -1,7 +1,7 @@
 package multisig
 
 import (
-	"strings"
+	"fmt"
 
 	"github.com/cosmos/cosmos-sdk/codec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -10,10 +10,15 @@ import (
 
 // query endpoints supported by the multisig Querier
 const (
-	ListWallets      = "listWallets"
-	GetWallet        = "getWallet"
-	ListTransactions = "listTransactions"
-	GetTransaction   = "getTransaction"
+	ListWallets       = "listWallets"
+	GetWallet         = "getWallet"
+	ListTransactions  = "listTransactions"
+	GetTransaction    = "getTransaction"
+	QueryParams       = "params"
+	ListWalletUpdates = "listWalletUpdates"
+	GetUnsignedTx     = "getUnsignedTx"
+	GetSignDoc        = "getSignDoc"
+	CheckInvariants   = "invariants"
 )
 
 // NewQuerier is the module level router for state queries
@@ -28,6 +33,16 @@ func NewQuerier(keeper Keeper) sdk.Querier {
 			return queryTransactions(ctx, path[1:], req, keeper)
 		case GetTransaction:
 			return getTransaction(ctx, path[1:], req, keeper)
+		case QueryParams:
+			return queryParams(ctx, keeper)
+		case ListWalletUpdates:
+			return queryWalletUpdates(ctx, path[1:], req, keeper)
+		case GetUnsignedTx:
+			return getUnsignedTx(ctx, path[1:], req, keeper)
+		case GetSignDoc:
+			return getSignDoc(ctx, path[1:], req, keeper)
+		case CheckInvariants:
+			return checkInvariants(ctx, keeper)
 		default:
 			return nil, sdk.ErrUnknownRequest("unknown multisig query endpoint")
 		}
@@ -35,23 +50,20 @@ func NewQuerier(keeper Keeper) sdk.Querier {
 }
 
 func queryWallets(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
-	var walletList QueryWallets
-
-	iterator := keeper.GetIterator(ctx)
-
-	for ; iterator.Valid(); iterator.Next() {
-		if strings.HasPrefix(string(iterator.Key()), "wallet-") {
-			address := strings.TrimPrefix(string(iterator.Key()), "wallet-")
-			wallet := keeper.GetWallet(ctx, address)
-			for _, pubkey := range wallet.PubKeys {
-				if pubkey == path[0] {
-					walletList = append(walletList, wallet)
-					break
-				}
-			}
-
+	var params QueryWalletsParams
+	if len(req.Data) > 0 {
+		err := keeper.cdc.UnmarshalJSON(req.Data, &params)
+		if err != nil {
+			return nil, sdk.ErrUnknownRequest(fmt.Sprintf("failed to parse params: %s", err))
 		}
 	}
+	if err := params.ValidateBasic(); err != nil {
+		return nil, sdk.ErrUnknownRequest(err.Error())
+	}
+
+	wallets := keeper.GetWalletsByPubKey(ctx, path[0])
+	start, end := paginate(len(wallets), params.Page, params.Limit)
+	walletList := QueryWallets(wallets[start:end])
 
 	res, err := codec.MarshalJSONIndent(keeper.cdc, walletList)
 	if err != nil {
@@ -63,6 +75,9 @@ func queryWallets(ctx sdk.Context, path []string, req abci.RequestQuery, keeper
 
 func getWallet(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
 	wallet := keeper.GetWallet(ctx, path[0])
+	if wallet.Address.Empty() {
+		return nil, ErrWalletNotFound(keeper.Codespace(), path[0])
+	}
 
 	res, err := codec.MarshalJSONIndent(keeper.cdc, wallet)
 	if err != nil {
@@ -73,20 +88,58 @@ func getWallet(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Kee
 }
 
 func queryTransactions(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
-	var transactionList QueryTransactions
+	address, err := sdk.AccAddressFromBech32(path[0])
+	if err != nil {
+		return nil, sdk.ErrInvalidAddress(err.Error())
+	}
 
-	iterator := keeper.GetIterator(ctx)
+	var params QueryTransactionsParams
+	if len(req.Data) > 0 {
+		err = keeper.cdc.UnmarshalJSON(req.Data, &params)
+		if err != nil {
+			return nil, sdk.ErrUnknownRequest(fmt.Sprintf("failed to parse params: %s", err))
+		}
+	}
+	if err = params.ValidateBasic(); err != nil {
+		return nil, sdk.ErrUnknownRequest(err.Error())
+	}
 
-	for ; iterator.Valid(); iterator.Next() {
-		if strings.HasPrefix(string(iterator.Key()), "transaction-") {
-			uidStr := strings.TrimPrefix(string(iterator.Key()), "transaction-")
-			transaction := keeper.GetTransaction(ctx, uidStr)
-			if transaction.From.String() == path[0] {
-				transactionList = append(transactionList, transaction)
-			}
+	skip := 0
+	if params.Limit > 0 && params.Page > 1 {
+		skip = (params.Page - 1) * params.Limit
+	}
 
+	var transactionList QueryTransactions
+	descending := params.Sort == SortDesc
+	// transactions to a recipient are looked up in the recipient index
+	iterate := func(cb func(Transaction) bool) {
+		keeper.IterateWalletTransactions(ctx, address, params.MinHeight, params.MaxHeight, descending, cb)
+	}
+	if !params.Recipient.Empty() {
+		iterate = func(cb func(Transaction) bool) {
+			keeper.IterateRecipientTransactions(ctx, params.Recipient, params.MinHeight, params.MaxHeight, descending, cb)
 		}
 	}
+	iterate(func(transaction Transaction) bool {
+		if !transaction.From.Equals(address) {
+			return false
+		}
+		if params.Status != "" && transaction.Status.String() != params.Status {
+			return false
+		}
+		if params.Denom != "" && transaction.Coins.AmountOf(params.Denom).IsZero() {
+			return false
+		}
+		if skip > 0 {
+			skip--
+			return false
+		}
+		transactionList = append(transactionList, QueryTransaction{
+			Transaction: transaction,
+			Stale:       keeper.IsStale(ctx, transaction),
+		})
+		return params.Limit > 0 && len(transactionList) >= params.Limit
+	})
 
 	res, err := codec.MarshalJSONIndent(keeper.cdc, transactionList)
 	if err != nil {
@@ -97,13 +150,116 @@ func queryTransactions(ctx sdk.Context, path []string, req abci.RequestQuery, ke
 }
 
 func getTransaction(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
+	transaction := keeper.GetTransaction(ctx, path[0])
+	if transaction.From.Empty() {
+		return nil, ErrTxNotFound(keeper.Codespace())
+	}
+	res, err := codec.MarshalJSONIndent(keeper.cdc, QueryTransaction{
+		Transaction: transaction,
+		Stale:       keeper.IsStale(ctx, transaction),
+	})
+	if err != nil {
+		panic("could not marshal result to JSON")
+	}
+
+	return res, nil
+}
 
+func getUnsignedTx(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
 	transaction := keeper.GetTransaction(ctx, path[0])
+	if transaction.From.Empty() {
+		return nil, ErrTxNotFound(keeper.Codespace())
+	}
 
-	res, err := codec.MarshalJSONIndent(keeper.cdc, transaction)
+	res, err := codec.MarshalJSONIndent(keeper.cdc, transaction.GetStdTx())
 	if err != nil {
 		panic("could not marshal result to JSON")
 	}
 
 	return res, nil
 }
+
+// returns the exact sign bytes of the transaction, which are the sorted JSON
+// encoding of its StdSignDoc
+func getSignDoc(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
+	transaction := keeper.GetTransaction(ctx, path[0])
+	if transaction.From.Empty() {
+		return nil, ErrTxNotFound(keeper.Codespace())
+	}
+	if err := checkSignable(ctx, keeper, transaction); err != nil {
+		return nil, err
+	}
+	// signatures of a stale request are verified once it is repinned to the
+	// current sequence of its wallet, so its sign doc is built for it
+	keeper.RefreshTransaction(ctx, &transaction)
+
+	return keeper.GetSignBytes(ctx, transaction), nil
+}
+
+// runs the multisig invariants against the queried state, without halting the
+// chain when one is broken
+func checkInvariants(ctx sdk.Context, keeper Keeper) ([]byte, sdk.Error) {
+	var results QueryInvariants
+	for _, r := range invariantRoutes(keeper) {
+		result := InvariantResult{Route: r.route}
+		if err := r.invar(ctx); err != nil {
+			result.Broken = true
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	res, err := codec.MarshalJSONIndent(keeper.cdc, results)
+	if err != nil {
+		panic("could not marshal result to JSON")
+	}
+
+	return res, nil
+}
+
+func queryParams(ctx sdk.Context, keeper Keeper) ([]byte, sdk.Error) {
+	params := keeper.GetParams(ctx)
+
+	res, err := codec.MarshalJSONIndent(keeper.cdc, params)
+	if err != nil {
+		panic("could not marshal result to JSON")
+	}
+
+	return res, nil
+}
+
+func queryWalletUpdates(ctx sdk.Context, path []string, req abci.RequestQuery, keeper Keeper) ([]byte, sdk.Error) {
+	address, err := sdk.AccAddressFromBech32(path[0])
+	if err != nil {
+		return nil, sdk.ErrInvalidAddress(err.Error())
+	}
+
+	updateList := QueryWalletUpdates(keeper.GetWalletUpdates(ctx, address))
+
+	res, err := codec.MarshalJSONIndent(keeper.cdc, updateList)
+	if err != nil {
+		panic("could not marshal result to JSON")
+	}
+
+	return res, nil
+}
+
+// returns the bounds of a page of a list of the given length. Pages start at
+// one, and a limit of zero returns the whole list.
+func paginate(length, page, limit int) (start, end int) {
+	if limit == 0 {
+		return 0, length
+	}
+	if page < 1 {
+		page = 1
+	}
+	start = (page - 1) * limit
+	if start > length {
+		start = length
+	}
+	end = start + limit
+	if end > length {
+		end = length
+	}
+	return start, end
+}
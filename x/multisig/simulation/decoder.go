@@ -0,0 +1,56 @@
+package simulation
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/cbarraford/cosmos-multisig/x/multisig"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	cmn "github.com/tendermint/tendermint/libs/common"
+)
+
+// DecodeStore unmarshals the values of a key of the multisig store in two
+// stores, to print the difference between them
+func DecodeStore(cdc *codec.Codec, kvA, kvB cmn.KVPair) string {
+	switch {
+	case bytes.Equal(kvA.Key[:1], multisig.SchemaVersionKey),
+		bytes.Equal(kvA.Key[:1], multisig.TransactionCountKeyPrefix):
+		var a, b uint64
+		cdc.MustUnmarshalBinaryBare(kvA.Value, &a)
+		cdc.MustUnmarshalBinaryBare(kvB.Value, &b)
+		return fmt.Sprintf("%d\n%d", a, b)
+
+	case bytes.Equal(kvA.Key[:1], multisig.WalletKeyPrefix):
+		var walletA, walletB multisig.MultiSigWallet
+		cdc.MustUnmarshalBinaryBare(kvA.Value, &walletA)
+		cdc.MustUnmarshalBinaryBare(kvB.Value, &walletB)
+		return fmt.Sprintf("%v\n%v", walletA, walletB)
+
+	case bytes.Equal(kvA.Key[:1], multisig.TransactionKeyPrefix):
+		var transactionA, transactionB multisig.Transaction
+		cdc.MustUnmarshalBinaryBare(kvA.Value, &transactionA)
+		cdc.MustUnmarshalBinaryBare(kvB.Value, &transactionB)
+		return fmt.Sprintf("%v\n%v", transactionA, transactionB)
+
+	case bytes.Equal(kvA.Key[:1], multisig.WalletUpdateKeyPrefix):
+		var updateA, updateB multisig.WalletUpdate
+		cdc.MustUnmarshalBinaryBare(kvA.Value, &updateA)
+		cdc.MustUnmarshalBinaryBare(kvB.Value, &updateB)
+		return fmt.Sprintf("%v\n%v", updateA, updateB)
+
+	// the pubkey index holds wallet addresses
+	case bytes.Equal(kvA.Key[:1], multisig.WalletPubKeyKeyPrefix):
+		return fmt.Sprintf("%s\n%s", sdk.AccAddress(kvA.Value), sdk.AccAddress(kvB.Value))
+
+	// the other indexes hold transaction uuids
+	case bytes.Equal(kvA.Key[:1], multisig.WalletTransactionKeyPrefix),
+		bytes.Equal(kvA.Key[:1], multisig.RecipientTxKeyPrefix),
+		bytes.Equal(kvA.Key[:1], multisig.ExpiryQueueKeyPrefix),
+		bytes.Equal(kvA.Key[:1], multisig.OpenTransactionKeyPrefix):
+		return fmt.Sprintf("%s\n%s", kvA.Value, kvB.Value)
+
+	default:
+		panic(fmt.Sprintf("invalid multisig key prefix %X", kvA.Key[:1]))
+	}
+}
@@ -0,0 +1,51 @@
+package simulation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+
+	"github.com/cbarraford/cosmos-multisig/x/multisig"
+	"github.com/cosmos/cosmos-sdk/codec"
+	"github.com/cosmos/cosmos-sdk/x/simulation"
+)
+
+// RandomizedGenState generates a random multisig genesis state, with random
+// params and wallets of the simulation accounts
+func RandomizedGenState(cdc *codec.Codec, r *rand.Rand, accs []simulation.Account, genesisState map[string]json.RawMessage) {
+	params := multisig.NewParams(
+		int64(simulation.RandIntBetween(r, 2, 21)),
+		int64(simulation.RandIntBetween(r, 1, 101)),
+		// short lifetimes, so requests expire during the simulation
+		int64(simulation.RandIntBetween(r, 10, 500)),
+		[]string{},
+	)
+
+	var wallets []multisig.MultiSigWallet
+	addresses := make(map[string]bool)
+	numWallets := r.Intn(10)
+	for i := 0; i < numWallets; i++ {
+		members := randomMembers(r, accs, int(params.MaxPubKeys))
+		if len(members) == 0 {
+			break
+		}
+		wallet, err := multisig.NewMultiSigWallet(
+			simulation.RandStringOfLength(r, 10),
+			memberPubKeys(members),
+			simulation.RandIntBetween(r, 1, len(members)+1),
+		)
+		if err != nil {
+			panic(err)
+		}
+		if addresses[wallet.Address.String()] {
+			continue
+		}
+		addresses[wallet.Address.String()] = true
+		wallets = append(wallets, wallet)
+	}
+
+	multisigGenesis := multisig.NewGenesisState(params, wallets, nil, nil, nil)
+
+	fmt.Printf("Selected randomly generated multisig parameters:\n%s\n", codec.MustMarshalJSONIndent(cdc, params))
+	genesisState[multisig.ModuleName] = cdc.MustMarshalJSON(multisigGenesis)
+}
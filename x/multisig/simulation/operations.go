@@ -0,0 +1,292 @@
+package simulation
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math/rand"
+
+	"github.com/cbarraford/cosmos-multisig/x/multisig"
+	"github.com/cosmos/cosmos-sdk/baseapp"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/cosmos-sdk/x/simulation"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+)
+
+// SimulateMsgCreateWallet creates a wallet of random simulation accounts
+func SimulateMsgCreateWallet(k multisig.Keeper) simulation.Operation {
+	handler := multisig.NewHandler(k)
+	return func(r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context,
+		accs []simulation.Account) (opMsg simulation.OperationMsg, fOps []simulation.FutureOperation, err error) {
+
+		members := randomMembers(r, accs, int(k.GetParams(ctx).MaxPubKeys))
+		if len(members) == 0 {
+			return simulation.NoOpMsg(), nil, nil
+		}
+		msg := multisig.NewMsgCreateWallet(
+			simulation.RandStringOfLength(r, 10),
+			memberPubKeys(members),
+			simulation.RandIntBetween(r, 1, len(members)+1),
+			[]sdk.AccAddress{members[0].Address},
+		)
+
+		if msg.ValidateBasic() != nil {
+			return simulation.NoOpMsg(), nil, fmt.Errorf("expected msg to pass ValidateBasic: %s", msg.GetSignBytes())
+		}
+
+		ctx, write := ctx.CacheContext()
+		ok := handler(ctx, msg).IsOK()
+		if ok {
+			write()
+		}
+
+		opMsg = simulation.NewOperationMsg(msg, ok, "")
+		return opMsg, nil, nil
+	}
+}
+
+// SimulateMsgCreateTransaction creates a request sending coins from a random
+// wallet to a random account. Wallets without coins are funded first by a
+// random account.
+func SimulateMsgCreateTransaction(bk bank.Keeper, k multisig.Keeper) simulation.Operation {
+	handler := multisig.NewHandler(k)
+	bankHandler := bank.NewHandler(bk)
+	return func(r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context,
+		accs []simulation.Account) (opMsg simulation.OperationMsg, fOps []simulation.FutureOperation, err error) {
+
+		wallets := k.GetAllWallets(ctx)
+		if len(wallets) == 0 {
+			return simulation.NoOpMsg(), nil, nil
+		}
+		wallet := wallets[r.Intn(len(wallets))]
+		member, err := multisig.PubKeyAddress(wallet.PubKeys[r.Intn(len(wallet.PubKeys))])
+		if err != nil {
+			return simulation.NoOpMsg(), nil, err
+		}
+
+		ctx, write := ctx.CacheContext()
+
+		if bk.GetCoins(ctx, wallet.Address).IsZero() {
+			funder := simulation.RandomAcc(r, accs)
+			amount := simulation.RandomAmount(r, bk.GetCoins(ctx, funder.Address).AmountOf(sdk.DefaultBondDenom))
+			if !amount.IsPositive() {
+				return simulation.NoOpMsg(), nil, nil
+			}
+			deposit := bank.MsgSend{
+				FromAddress: funder.Address,
+				ToAddress:   wallet.Address,
+				Amount:      sdk.NewCoins(sdk.NewCoin(sdk.DefaultBondDenom, amount)),
+			}
+			if !bankHandler(ctx, deposit).IsOK() {
+				return simulation.NoOpMsg(), nil, nil
+			}
+		}
+
+		amount := simulation.RandomAmount(r, bk.GetCoins(ctx, wallet.Address).AmountOf(sdk.DefaultBondDenom))
+		if !amount.IsPositive() {
+			return simulation.NoOpMsg(), nil, nil
+		}
+		msg := multisig.NewMsgCreateTransaction(
+			wallet.Address,
+			simulation.RandomAcc(r, accs).Address,
+			sdk.NewCoins(sdk.NewCoin(sdk.DefaultBondDenom, amount)),
+			simulation.RandStringOfLength(r, 10),
+			auth.StdFee{},
+			[]sdk.AccAddress{member},
+		)
+
+		if msg.ValidateBasic() != nil {
+			return simulation.NoOpMsg(), nil, fmt.Errorf("expected msg to pass ValidateBasic: %s", msg.GetSignBytes())
+		}
+
+		ok := handler(ctx, msg).IsOK()
+		if ok {
+			write()
+		}
+
+		opMsg = simulation.NewOperationMsg(msg, ok, "")
+		return opMsg, nil, nil
+	}
+}
+
+// SimulateMsgSignTransaction signs a random request with the key of a member
+// who has not signed it yet
+func SimulateMsgSignTransaction(k multisig.Keeper) simulation.Operation {
+	handler := multisig.NewHandler(k)
+	return func(r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context,
+		accs []simulation.Account) (opMsg simulation.OperationMsg, fOps []simulation.FutureOperation, err error) {
+
+		var signable []multisig.Transaction
+		for _, transaction := range k.GetAllTransactions(ctx) {
+			// stale ready requests may have been broadcast, they are not signed
+			if transaction.Status == multisig.StatusReadyToBroadcast && k.IsStale(ctx, transaction) {
+				continue
+			}
+			if transaction.Status.IsSignable() {
+				signable = append(signable, transaction)
+			}
+		}
+		if len(signable) == 0 {
+			return simulation.NoOpMsg(), nil, nil
+		}
+		transaction := signable[r.Intn(len(signable))]
+		wallet := k.GetWallet(ctx, transaction.From.String())
+
+		// signatures of stale requests are removed before the new one is
+		// verified, so the member signs the refreshed request
+		k.RefreshTransaction(ctx, &transaction)
+		var signers []simulation.Account
+		for _, pubkey := range wallet.PubKeys {
+			member, err := multisig.PubKeyAddress(pubkey)
+			if err != nil {
+				return simulation.NoOpMsg(), nil, err
+			}
+			acc, ok := findAccount(accs, member)
+			if ok && !transaction.HasSigned(pubkey) && !transaction.HasRejected(member) {
+				signers = append(signers, acc)
+			}
+		}
+		if len(signers) == 0 {
+			return simulation.NoOpMsg(), nil, nil
+		}
+		signer := signers[r.Intn(len(signers))]
+
+		sig, err := signer.PrivKey.Sign(k.GetSignBytes(ctx, transaction))
+		if err != nil {
+			return simulation.NoOpMsg(), nil, err
+		}
+		pubkey, pubkeyBase64, err := multisig.EncodePubKey(signer.PubKey)
+		if err != nil {
+			return simulation.NoOpMsg(), nil, err
+		}
+		msg := multisig.NewMsgSignTransaction(
+			transaction.UUID,
+			pubkey,
+			pubkeyBase64,
+			base64.StdEncoding.EncodeToString(sig),
+			[]sdk.AccAddress{signer.Address},
+		)
+
+		if msg.ValidateBasic() != nil {
+			return simulation.NoOpMsg(), nil, fmt.Errorf("expected msg to pass ValidateBasic: %s", msg.GetSignBytes())
+		}
+
+		ctx, write := ctx.CacheContext()
+		ok := handler(ctx, msg).IsOK()
+		if ok {
+			write()
+		}
+
+		opMsg = simulation.NewOperationMsg(msg, ok, "")
+		return opMsg, nil, nil
+	}
+}
+
+// SimulateMsgCompleteTransaction broadcasts a random request that is ready to
+// be broadcast, with the multisignature of its signatures, and completes it
+// with the hash of the broadcast tx
+func SimulateMsgCompleteTransaction(cdc *codec.Codec, k multisig.Keeper) simulation.Operation {
+	handler := multisig.NewHandler(k)
+	return func(r *rand.Rand, app *baseapp.BaseApp, ctx sdk.Context,
+		accs []simulation.Account) (opMsg simulation.OperationMsg, fOps []simulation.FutureOperation, err error) {
+
+		var ready []multisig.Transaction
+		for _, transaction := range k.GetAllTransactions(ctx) {
+			if transaction.Status == multisig.StatusReadyToBroadcast && !k.IsStale(ctx, transaction) {
+				ready = append(ready, transaction)
+			}
+		}
+		if len(ready) == 0 {
+			return simulation.NoOpMsg(), nil, nil
+		}
+		transaction := ready[r.Intn(len(ready))]
+		wallet := k.GetWallet(ctx, transaction.From.String())
+
+		multisignature, err := wallet.Multisign(transaction.Signatures)
+		if err != nil {
+			return simulation.NoOpMsg(), nil, err
+		}
+		multikey, err := wallet.PubKey()
+		if err != nil {
+			return simulation.NoOpMsg(), nil, err
+		}
+		tx := transaction.GetStdTx()
+		tx.Signatures = []auth.StdSignature{{PubKey: multikey, Signature: multisignature}}
+		res := app.Deliver(tx)
+		if !res.IsOK() {
+			// the wallet may no longer hold the coins of the request
+			return simulation.NoOpMsg(), nil, nil
+		}
+
+		member, err := multisig.PubKeyAddress(wallet.PubKeys[r.Intn(len(wallet.PubKeys))])
+		if err != nil {
+			return simulation.NoOpMsg(), nil, err
+		}
+		msg := multisig.NewMsgCompleteTransaction(
+			transaction.UUID,
+			fmt.Sprintf("%X", tmhash.Sum(cdc.MustMarshalBinaryLengthPrefixed(tx))),
+			ctx.BlockHeight(),
+			int64(res.GasUsed),
+			uint32(res.Code),
+			[]sdk.AccAddress{member},
+		)
+
+		if msg.ValidateBasic() != nil {
+			return simulation.NoOpMsg(), nil, fmt.Errorf("expected msg to pass ValidateBasic: %s", msg.GetSignBytes())
+		}
+
+		ctx, write := ctx.CacheContext()
+		ok := handler(ctx, msg).IsOK()
+		if ok {
+			write()
+		}
+
+		opMsg = simulation.NewOperationMsg(msg, ok, "")
+		return opMsg, nil, nil
+	}
+}
+
+// returns up to max distinct random accounts with secp256k1 keys, as the
+// ante handler does not verify ed25519 signatures within multisignatures
+func randomMembers(r *rand.Rand, accs []simulation.Account, max int) []simulation.Account {
+	var candidates []simulation.Account
+	for _, acc := range accs {
+		if _, ok := acc.PubKey.(secp256k1.PubKeySecp256k1); ok {
+			candidates = append(candidates, acc)
+		}
+	}
+	if len(candidates) == 0 || max < 1 {
+		return nil
+	}
+	if max > len(candidates) {
+		max = len(candidates)
+	}
+	n := simulation.RandIntBetween(r, 1, max+1)
+	members := make([]simulation.Account, n)
+	for i, j := range r.Perm(len(candidates))[:n] {
+		members[i] = candidates[j]
+	}
+	return members
+}
+
+// returns the bech32 account pubkeys of accounts
+func memberPubKeys(members []simulation.Account) []string {
+	pubkeys := make([]string, len(members))
+	for i, member := range members {
+		pubkeys[i] = sdk.MustBech32ifyAccPub(member.PubKey)
+	}
+	return pubkeys
+}
+
+// returns the simulation account of an address
+func findAccount(accs []simulation.Account, address sdk.AccAddress) (simulation.Account, bool) {
+	for _, acc := range accs {
+		if acc.Address.Equals(address) {
+			return acc, true
+		}
+	}
+	return simulation.Account{}, false
+}
@@ -0,0 +1,21 @@
+package tags
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Multisig tags, the action tag (the msg type) is added by the baseapp
+var (
+	TxCategory = "multisig"
+
+	Action           = sdk.TagAction
+	Category         = sdk.TagCategory
+	Wallet           = "wallet"
+	Successor        = "successor"
+	TransactionID    = "transaction_id"
+	Signer           = "signer"
+	Recipient        = "recipient"
+	Status           = "status"
+	ThresholdReached = "threshold_reached"
+	Expired          = "expired"
+)
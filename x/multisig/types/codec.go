@@ -2,18 +2,45 @@ package types
 
 import (
 	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	distr "github.com/cosmos/cosmos-sdk/x/distribution"
+	"github.com/cosmos/cosmos-sdk/x/slashing"
+	"github.com/cosmos/cosmos-sdk/x/staking"
 )
 
 var ModuleCdc = codec.New()
 
+// RequestableRoutes are the routes of the msgs transaction requests can hold.
+// The codecs of their modules are registered on ModuleCdc, which encodes the
+// requests in the genesis state.
+var RequestableRoutes = []string{
+	bank.RouterKey,
+	staking.RouterKey,
+	distr.RouterKey,
+	slashing.RouterKey,
+}
+
 func init() {
 	RegisterCodec(ModuleCdc)
+	// transaction requests hold the msgs of the RequestableRoutes
+	sdk.RegisterCodec(ModuleCdc)
+	bank.RegisterCodec(ModuleCdc)
+	staking.RegisterCodec(ModuleCdc)
+	distr.RegisterCodec(ModuleCdc)
+	slashing.RegisterCodec(ModuleCdc)
+	codec.RegisterCrypto(ModuleCdc)
 }
 
 // RegisterCodec registers concrete types on the Amino codec
 func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MsgCreateWallet{}, "multisig/CreateWallet", nil)
 	cdc.RegisterConcrete(MsgCreateTransaction{}, "multisig/CreateTransaction", nil)
+	cdc.RegisterConcrete(MsgCreateMultiSendTransaction{}, "multisig/CreateMultiSendTransaction", nil)
+	cdc.RegisterConcrete(MsgCreateMsgsTransaction{}, "multisig/CreateMsgsTransaction", nil)
 	cdc.RegisterConcrete(MsgSignTransaction{}, "multisig/SignTransaction", nil)
 	cdc.RegisterConcrete(MsgCompleteTransaction{}, "multisig/CompleteTransaction", nil)
+	cdc.RegisterConcrete(MsgCancelTransaction{}, "multisig/CancelTransaction", nil)
+	cdc.RegisterConcrete(MsgRejectTransaction{}, "multisig/RejectTransaction", nil)
+	cdc.RegisterConcrete(MsgProposeWalletUpdate{}, "multisig/ProposeWalletUpdate", nil)
 }
@@ -0,0 +1,106 @@
+package types
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Local code type
+type CodeType = sdk.CodeType
+
+const (
+	// Default multisig codespace
+	DefaultCodespace sdk.CodespaceType = ModuleName
+
+	CodeWalletExists        CodeType = 101
+	CodeWalletNotFound      CodeType = 102
+	CodeInvalidWallet       CodeType = 103
+	CodeWalletReplaced      CodeType = 104
+	CodeInvalidWalletUpdate CodeType = 105
+	CodeTxExists            CodeType = 106
+	CodeTxNotFound          CodeType = 107
+	CodeInvalidTx           CodeType = 108
+	CodeNotMember           CodeType = 109
+	CodeInvalidSigner       CodeType = 110
+	CodeInvalidSignature    CodeType = 111
+	CodeThresholdNotMet     CodeType = 112
+	CodeAlreadyCompleted    CodeType = 113
+	CodeInvalidStatus       CodeType = 114
+	CodeConflictingVote     CodeType = 115
+	CodeTooManyPending      CodeType = 116
+	CodeDenomNotAllowed     CodeType = 117
+	CodeSequenceNotUsed     CodeType = 118
+)
+
+func ErrWalletExists(codespace sdk.CodespaceType) sdk.Error {
+	return sdk.NewError(codespace, CodeWalletExists, "Wallet already exists")
+}
+
+func ErrWalletNotFound(codespace sdk.CodespaceType, address string) sdk.Error {
+	return sdk.NewError(codespace, CodeWalletNotFound, fmt.Sprintf("No registered multi-signature wallet for %s", address))
+}
+
+func ErrInvalidWallet(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidWallet, msg)
+}
+
+func ErrWalletReplaced(codespace sdk.CodespaceType, successor sdk.AccAddress) sdk.Error {
+	return sdk.NewError(codespace, CodeWalletReplaced, fmt.Sprintf("Wallet has already been replaced by %s", successor))
+}
+
+func ErrInvalidWalletUpdate(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidWalletUpdate, msg)
+}
+
+func ErrTxExists(codespace sdk.CodespaceType) sdk.Error {
+	return sdk.NewError(codespace, CodeTxExists, "Transaction already exists")
+}
+
+func ErrTxNotFound(codespace sdk.CodespaceType) sdk.Error {
+	return sdk.NewError(codespace, CodeTxNotFound, "No transaction found.")
+}
+
+func ErrInvalidTx(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidTx, msg)
+}
+
+func ErrNotMember(codespace sdk.CodespaceType, address sdk.AccAddress) sdk.Error {
+	return sdk.NewError(codespace, CodeNotMember, fmt.Sprintf("%s is not a member of the wallet", address))
+}
+
+func ErrInvalidSigner(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidSigner, msg)
+}
+
+func ErrInvalidSignature(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidSignature, fmt.Sprintf("Invalid signature: %s", msg))
+}
+
+func ErrThresholdNotMet(codespace sdk.CodespaceType, status TransactionStatus) sdk.Error {
+	return sdk.NewError(codespace, CodeThresholdNotMet, fmt.Sprintf("Transaction does not have enough signatures (%s)", status))
+}
+
+func ErrAlreadyCompleted(codespace sdk.CodespaceType, txID string) sdk.Error {
+	return sdk.NewError(codespace, CodeAlreadyCompleted, fmt.Sprintf("Transaction was already completed by %s", txID))
+}
+
+func ErrInvalidStatus(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeInvalidStatus, msg)
+}
+
+func ErrConflictingVote(codespace sdk.CodespaceType, msg string) sdk.Error {
+	return sdk.NewError(codespace, CodeConflictingVote, msg)
+}
+
+func ErrTooManyPending(codespace sdk.CodespaceType, max int64) sdk.Error {
+	return sdk.NewError(codespace, CodeTooManyPending, fmt.Sprintf("Wallet cannot have more than %d pending transactions", max))
+}
+
+func ErrDenomNotAllowed(codespace sdk.CodespaceType, denom string) sdk.Error {
+	return sdk.NewError(codespace, CodeDenomNotAllowed, fmt.Sprintf("Denom %s is not allowed", denom))
+}
+
+func ErrSequenceNotUsed(codespace sdk.CodespaceType) sdk.Error {
+	return sdk.NewError(codespace, CodeSequenceNotUsed, "Wallet has not sent a transaction with the pinned sequence")
+}
@@ -1,9 +1,161 @@
 package types
 
+import (
+	"encoding/binary"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
 const (
 	// module name
 	ModuleName = "multisig"
 
 	// StoreKey to be used when creating the KVStore
 	StoreKey = ModuleName
+
+	// SchemaVersion is the version of the store layout written by this
+	// version of the module
+	SchemaVersion uint64 = 2
+)
+
+// Keys of the multisig store. Every key starts with a one byte prefix;
+// addresses within keys have a fixed length of sdk.AddrLen bytes.
+var (
+	// key of the store layout version
+	SchemaVersionKey = []byte{0x00}
+
+	// prefixes of the wallet and transaction records
+	WalletKeyPrefix           = []byte{0x01}
+	TransactionKeyPrefix      = []byte{0x02}
+	TransactionCountKeyPrefix = []byte{0x03}
+	WalletUpdateKeyPrefix     = []byte{0x04}
+
+	// prefixes of the indexes derived from the records
+	WalletPubKeyKeyPrefix      = []byte{0x10}
+	WalletTransactionKeyPrefix = []byte{0x11}
+	RecipientTxKeyPrefix       = []byte{0x12}
+	ExpiryQueueKeyPrefix       = []byte{0x13}
+	OpenTransactionKeyPrefix   = []byte{0x14}
 )
+
+// IndexKeyPrefixes are the prefixes of the index keys, which are derived from
+// the wallet and transaction records
+var IndexKeyPrefixes = [][]byte{
+	WalletPubKeyKeyPrefix,
+	WalletTransactionKeyPrefix,
+	RecipientTxKeyPrefix,
+	ExpiryQueueKeyPrefix,
+	OpenTransactionKeyPrefix,
+}
+
+// WalletKey is the key of a wallet record
+func WalletKey(address sdk.AccAddress) []byte {
+	return append(copyKey(WalletKeyPrefix), address...)
+}
+
+// TransactionKey is the key of a transaction record
+func TransactionKey(uid string) []byte {
+	return append(copyKey(TransactionKeyPrefix), uid...)
+}
+
+// TransactionCountKey is the key of the number of transactions created for a
+// wallet
+func TransactionCountKey(address sdk.AccAddress) []byte {
+	return append(copyKey(TransactionCountKeyPrefix), address...)
+}
+
+// WalletUpdatesKey is the prefix of the update proposals of a wallet
+func WalletUpdatesKey(wallet sdk.AccAddress) []byte {
+	return append(copyKey(WalletUpdateKeyPrefix), wallet...)
+}
+
+// WalletUpdateKey is the key of a proposal to replace a wallet by a successor
+// wallet
+func WalletUpdateKey(wallet, successor sdk.AccAddress) []byte {
+	return append(WalletUpdatesKey(wallet), successor...)
+}
+
+// PubKeyWalletsKey is the prefix of the wallets a pubkey is a member of. The
+// pubkey is length prefixed, as its encoding has no fixed length.
+func PubKeyWalletsKey(pubkey string) []byte {
+	key := append(copyKey(WalletPubKeyKeyPrefix), byte(len(pubkey)))
+	return append(key, pubkey...)
+}
+
+// WalletPubKeyKey is the index key of a member of a wallet
+func WalletPubKeyKey(pubkey string, wallet sdk.AccAddress) []byte {
+	return append(PubKeyWalletsKey(pubkey), wallet...)
+}
+
+// WalletTransactionsKey is the prefix of the transactions of a wallet
+func WalletTransactionsKey(address sdk.AccAddress) []byte {
+	return append(copyKey(WalletTransactionKeyPrefix), address...)
+}
+
+// WalletTransactionsHeightKey is the prefix of the transactions of a wallet
+// created at a height
+func WalletTransactionsHeightKey(address sdk.AccAddress, height int64) []byte {
+	return append(WalletTransactionsKey(address), HeightBytes(height)...)
+}
+
+// WalletTransactionKey is the index key of a transaction of a wallet, sorted
+// by creation height
+func WalletTransactionKey(address sdk.AccAddress, height int64, uid string) []byte {
+	return append(WalletTransactionsHeightKey(address, height), uid...)
+}
+
+// RecipientTransactionsKey is the prefix of the transactions sending funds to
+// a recipient
+func RecipientTransactionsKey(address sdk.AccAddress) []byte {
+	return append(copyKey(RecipientTxKeyPrefix), address...)
+}
+
+// RecipientTransactionsHeightKey is the prefix of the transactions sending
+// funds to a recipient created at a height
+func RecipientTransactionsHeightKey(address sdk.AccAddress, height int64) []byte {
+	return append(RecipientTransactionsKey(address), HeightBytes(height)...)
+}
+
+// RecipientTransactionKey is the index key of a transaction sending funds to
+// a recipient, sorted by creation height
+func RecipientTransactionKey(address sdk.AccAddress, height int64, uid string) []byte {
+	return append(RecipientTransactionsHeightKey(address, height), uid...)
+}
+
+// ExpiryQueueHeightKey is the prefix of the queued transactions created at a
+// height
+func ExpiryQueueHeightKey(height int64) []byte {
+	return append(copyKey(ExpiryQueueKeyPrefix), HeightBytes(height)...)
+}
+
+// ExpiryQueueKey is the key of a transaction in the queue of the transactions
+// that can expire, sorted by creation height
+func ExpiryQueueKey(height int64, uid string) []byte {
+	return append(ExpiryQueueHeightKey(height), uid...)
+}
+
+// OpenTransactionsKey is the prefix of the open transactions of a wallet,
+// which may still expire
+func OpenTransactionsKey(address sdk.AccAddress) []byte {
+	return append(copyKey(OpenTransactionKeyPrefix), address...)
+}
+
+// OpenTransactionKey is the index key of an open transaction of a wallet
+func OpenTransactionKey(address sdk.AccAddress, uid string) []byte {
+	return append(OpenTransactionsKey(address), uid...)
+}
+
+// HeightBytes is the lexicographically sortable encoding of a height, which
+// may be negative after a zero height export
+func HeightBytes(height int64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(height)^(1<<63))
+	return bz
+}
+
+// returns a copy of a prefix, so keys built from it do not share its array
+func copyKey(prefix []byte) []byte {
+	key := make([]byte, len(prefix), len(prefix)+64)
+	copy(key, prefix)
+	return key
+}
@@ -1,8 +1,15 @@
 package types
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/google/uuid"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
 )
 
 const RouterKey = ModuleName // this was defined in your key.go file
@@ -34,13 +41,13 @@ func (msg MsgCreateWallet) Type() string { return "set_wallet" }
 // ValidateBasic runs stateless checks on the message
 func (msg MsgCreateWallet) ValidateBasic() sdk.Error {
 	if len(msg.PubKeys) < msg.MinSigTx {
-		return sdk.ErrUnknownRequest("Minimum signature transaction number cannot be larger than the number of public keys")
+		return ErrInvalidWallet(DefaultCodespace, "Minimum signature transaction number cannot be larger than the number of public keys")
 	}
 	if msg.MinSigTx < 1 {
-		return sdk.ErrUnknownRequest("Must require at least 1 signatures")
+		return ErrInvalidWallet(DefaultCodespace, "Must require at least 1 signatures")
 	}
 	if len(msg.Name) == 0 {
-		return sdk.ErrUnknownRequest("Name cannot be empty")
+		return ErrInvalidWallet(DefaultCodespace, "Name cannot be empty")
 	}
 	return nil
 }
@@ -55,24 +62,37 @@ func (msg MsgCreateWallet) GetSigners() []sdk.AccAddress {
 	return msg.Signers
 }
 
+// checks a message has signers, the handlers check they are wallet members
+func validateSigners(signers []sdk.AccAddress) sdk.Error {
+	if len(signers) == 0 {
+		return ErrInvalidSigner(DefaultCodespace, "Message must have at least one signer")
+	}
+	for _, signer := range signers {
+		if signer.Empty() {
+			return sdk.ErrInvalidAddress(signer.String())
+		}
+	}
+	return nil
+}
+
 // MsgCreateTransaction defines a CreateTransaction message
 type MsgCreateTransaction struct {
-	Amount  sdk.Int          `json:"amount"`
-	Denom   string           `json:"denom"`
+	Coins   sdk.Coins        `json:"coins"`
+	Fee     auth.StdFee      `json:"fee"`
 	From    sdk.AccAddress   `json:"from_address"`
+	Memo    string           `json:"memo"`
 	Signers []sdk.AccAddress `json:"signers"`
 	To      sdk.AccAddress   `json:"to_address"`
-	UUID    string           `json:"uuid"`
 }
 
 // NewMsgCreateTransaction is a constructor function for MsgCreateTransaction
-func NewMsgCreateTransaction(from, to sdk.AccAddress, amount sdk.Int, denom string, signers []sdk.AccAddress) MsgCreateTransaction {
+func NewMsgCreateTransaction(from, to sdk.AccAddress, coins sdk.Coins, memo string, fee auth.StdFee, signers []sdk.AccAddress) MsgCreateTransaction {
 	return MsgCreateTransaction{
-		UUID:    uuid.New().String(),
 		From:    from,
 		To:      to,
-		Amount:  amount,
-		Denom:   denom,
+		Coins:   coins,
+		Memo:    memo,
+		Fee:     fee,
 		Signers: signers,
 	}
 }
@@ -85,21 +105,34 @@ func (msg MsgCreateTransaction) Type() string { return "create_transaction" }
 
 // ValidateBasic runs stateless checks on the message
 func (msg MsgCreateTransaction) ValidateBasic() sdk.Error {
+	if err := validateSigners(msg.Signers); err != nil {
+		return err
+	}
 	if msg.From.Empty() {
 		return sdk.ErrInvalidAddress(msg.From.String())
 	}
 	if msg.To.Empty() {
 		return sdk.ErrInvalidAddress(msg.To.String())
 	}
-	/*
-		if msg.Coins.IsZero() {
-			return sdk.ErrUnknownRequest("Cannot have zero coins")
-		}
-		if msg.Coins.IsValid() {
-			// TODO: readd this back at some point
-			//return sdk.ErrUnknownRequest("Coins must be valid")
-		}
-	*/
+	if msg.Coins.Empty() {
+		return sdk.ErrInvalidCoins("Cannot have zero coins")
+	}
+	// valid coins are sorted by denom, without duplicates
+	if !msg.Coins.IsValid() {
+		return sdk.ErrInvalidCoins(msg.Coins.String())
+	}
+	if !msg.Coins.IsAllPositive() {
+		return sdk.ErrInvalidCoins(msg.Coins.String())
+	}
+	return ValidateRequestFee(msg.Fee)
+}
+
+// ValidateRequestFee checks the fee of a transaction request, a zero gas
+// stands for the default gas
+func ValidateRequestFee(fee auth.StdFee) sdk.Error {
+	if !fee.Amount.IsValid() {
+		return sdk.ErrInvalidCoins(fmt.Sprintf("invalid fee %s", fee.Amount))
+	}
 	return nil
 }
 
@@ -113,6 +146,162 @@ func (msg MsgCreateTransaction) GetSigners() []sdk.AccAddress {
 	return msg.Signers
 }
 
+// MsgCreateMultiSendTransaction defines a CreateMultiSendTransaction message
+type MsgCreateMultiSendTransaction struct {
+	Fee     auth.StdFee      `json:"fee"`
+	From    sdk.AccAddress   `json:"from_address"`
+	Memo    string           `json:"memo"`
+	Outputs []bank.Output    `json:"outputs"`
+	Signers []sdk.AccAddress `json:"signers"`
+}
+
+// NewMsgCreateMultiSendTransaction is a constructor function for MsgCreateMultiSendTransaction
+func NewMsgCreateMultiSendTransaction(from sdk.AccAddress, outputs []bank.Output, memo string, fee auth.StdFee, signers []sdk.AccAddress) MsgCreateMultiSendTransaction {
+	return MsgCreateMultiSendTransaction{
+		From:    from,
+		Outputs: outputs,
+		Memo:    memo,
+		Fee:     fee,
+		Signers: signers,
+	}
+}
+
+// Route should return the name of the module
+func (msg MsgCreateMultiSendTransaction) Route() string { return RouterKey }
+
+// Type should return the action
+func (msg MsgCreateMultiSendTransaction) Type() string { return "create_multisend_transaction" }
+
+// ValidateBasic runs stateless checks on the message
+func (msg MsgCreateMultiSendTransaction) ValidateBasic() sdk.Error {
+	if err := validateSigners(msg.Signers); err != nil {
+		return err
+	}
+	if msg.From.Empty() {
+		return sdk.ErrInvalidAddress(msg.From.String())
+	}
+	if len(msg.Outputs) == 0 {
+		return bank.ErrNoOutputs(bank.DefaultCodespace)
+	}
+	for _, output := range msg.Outputs {
+		if err := output.ValidateBasic(); err != nil {
+			return err
+		}
+	}
+	return ValidateRequestFee(msg.Fee)
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgCreateMultiSendTransaction) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgCreateMultiSendTransaction) GetSigners() []sdk.AccAddress {
+	return msg.Signers
+}
+
+// MsgCreateMsgsTransaction defines a CreateMsgsTransaction message, to
+// request the wallet to sign msgs of any other module
+type MsgCreateMsgsTransaction struct {
+	Fee     auth.StdFee      `json:"fee"`
+	From    sdk.AccAddress   `json:"from_address"`
+	Memo    string           `json:"memo"`
+	Msgs    []sdk.Msg        `json:"msgs"`
+	Signers []sdk.AccAddress `json:"signers"`
+}
+
+// NewMsgCreateMsgsTransaction is a constructor function for MsgCreateMsgsTransaction
+func NewMsgCreateMsgsTransaction(from sdk.AccAddress, msgs []sdk.Msg, memo string, fee auth.StdFee, signers []sdk.AccAddress) MsgCreateMsgsTransaction {
+	return MsgCreateMsgsTransaction{
+		From:    from,
+		Msgs:    msgs,
+		Memo:    memo,
+		Fee:     fee,
+		Signers: signers,
+	}
+}
+
+// Route should return the name of the module
+func (msg MsgCreateMsgsTransaction) Route() string { return RouterKey }
+
+// Type should return the action
+func (msg MsgCreateMsgsTransaction) Type() string { return "create_msgs_transaction" }
+
+// ValidateBasic runs stateless checks on the message
+func (msg MsgCreateMsgsTransaction) ValidateBasic() sdk.Error {
+	if err := validateSigners(msg.Signers); err != nil {
+		return err
+	}
+	if msg.From.Empty() {
+		return sdk.ErrInvalidAddress(msg.From.String())
+	}
+	if len(msg.Msgs) == 0 {
+		return ErrInvalidTx(DefaultCodespace, "Transaction must have at least one msg")
+	}
+	if err := ValidateWalletMsgs(msg.From, msg.Msgs); err != nil {
+		return err
+	}
+	return ValidateRequestFee(msg.Fee)
+}
+
+// ValidateWalletMsgs checks msgs are valid, of a requestable route and only
+// signed by the wallet
+func ValidateWalletMsgs(wallet sdk.AccAddress, msgs []sdk.Msg) sdk.Error {
+	for _, m := range msgs {
+		if !isRequestableRoute(m.Route()) {
+			return ErrInvalidTx(DefaultCodespace,
+				fmt.Sprintf("Msg %s of route %s cannot be requested, requestable routes are %s",
+					m.Type(), m.Route(), strings.Join(RequestableRoutes, ", ")),
+			)
+		}
+		if err := m.ValidateBasic(); err != nil {
+			return err
+		}
+		signers := m.GetSigners()
+		if len(signers) != 1 || !signers[0].Equals(wallet) {
+			return ErrInvalidSigner(DefaultCodespace,
+				fmt.Sprintf("Msg %s must only be signed by the wallet %s", m.Type(), wallet),
+			)
+		}
+	}
+	return nil
+}
+
+func isRequestableRoute(route string) bool {
+	for _, r := range RequestableRoutes {
+		if r == route {
+			return true
+		}
+	}
+	return false
+}
+
+// GetSignBytes encodes the message for signing, requested msgs are encoded
+// with their own sign bytes
+func (msg MsgCreateMsgsTransaction) GetSignBytes() []byte {
+	msgs := make([]json.RawMessage, len(msg.Msgs))
+	for i, m := range msg.Msgs {
+		msgs[i] = json.RawMessage(m.GetSignBytes())
+	}
+	bz, err := json.Marshal(struct {
+		Fee     json.RawMessage   `json:"fee"`
+		From    sdk.AccAddress    `json:"from_address"`
+		Memo    string            `json:"memo"`
+		Msgs    []json.RawMessage `json:"msgs"`
+		Signers []sdk.AccAddress  `json:"signers"`
+	}{json.RawMessage(msg.Fee.Bytes()), msg.From, msg.Memo, msgs, msg.Signers})
+	if err != nil {
+		panic(err)
+	}
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgCreateMsgsTransaction) GetSigners() []sdk.AccAddress {
+	return msg.Signers
+}
+
 // MsgSignTransaction defines a SignTransaction message
 type MsgSignTransaction struct {
 	PubKey       string           `json:"pubkey"`
@@ -142,16 +331,26 @@ func (msg MsgSignTransaction) Type() string { return "sign_transaction" }
 // ValidateBasic runs stateless checks on the message
 func (msg MsgSignTransaction) ValidateBasic() sdk.Error {
 	if len(msg.UUID) == 0 {
-		return sdk.ErrUnknownRequest("UUID cannot be blank")
+		return ErrInvalidTx(DefaultCodespace, "UUID cannot be blank")
 	}
 	if msg.PubKey == "" {
-		return sdk.ErrUnknownRequest("Pubkey cannot be blank")
+		return ErrInvalidSignature(DefaultCodespace, "Pubkey cannot be blank")
 	}
 	if msg.PubKeyBase64 == "" {
-		return sdk.ErrUnknownRequest("Pubkey (base64) cannot be blank")
+		return ErrInvalidSignature(DefaultCodespace, "Pubkey (base64) cannot be blank")
 	}
 	if msg.Signature == "" {
-		return sdk.ErrUnknownRequest("Signature cannot be blank")
+		return ErrInvalidSignature(DefaultCodespace, "Signature cannot be blank")
+	}
+	// the signature is submitted by the member it belongs to
+	signer, err := PubKeyAddress(msg.PubKey)
+	if err != nil {
+		return sdk.ErrInvalidPubKey(err.Error())
+	}
+	if len(msg.Signers) != 1 || !msg.Signers[0].Equals(signer) {
+		return ErrInvalidSigner(DefaultCodespace,
+			fmt.Sprintf("Signature must be submitted by the account of its pubkey (%s)", signer),
+		)
 	}
 	return nil
 }
@@ -168,16 +367,22 @@ func (msg MsgSignTransaction) GetSigners() []sdk.AccAddress {
 
 // MsgCompleteTransaction defines complete a transaction
 type MsgCompleteTransaction struct {
+	Code    uint32           `json:"code"`
+	GasUsed int64            `json:"gas_used"`
+	Height  int64            `json:"height"`
 	Signers []sdk.AccAddress `json:"signers"`
 	TxID    string           `json:"tx_id"`
 	UUID    string           `json:"uuid"`
 }
 
 // NewMsgCompleteTransaction is a constructor function for MsgCompleteTransaction
-func NewMsgCompleteTransaction(uid, txID string, signers []sdk.AccAddress) MsgCompleteTransaction {
+func NewMsgCompleteTransaction(uid, txID string, height, gasUsed int64, code uint32, signers []sdk.AccAddress) MsgCompleteTransaction {
 	return MsgCompleteTransaction{
 		UUID:    uid,
 		TxID:    txID,
+		Height:  height,
+		GasUsed: gasUsed,
+		Code:    code,
 		Signers: signers,
 	}
 }
@@ -186,15 +391,30 @@ func NewMsgCompleteTransaction(uid, txID string, signers []sdk.AccAddress) MsgCo
 func (msg MsgCompleteTransaction) Route() string { return RouterKey }
 
 // Type should return the action
-func (msg MsgCompleteTransaction) Type() string { return "sign_transaction" }
+func (msg MsgCompleteTransaction) Type() string { return "complete_transaction" }
 
 // ValidateBasic runs stateless checks on the message
 func (msg MsgCompleteTransaction) ValidateBasic() sdk.Error {
+	if err := validateSigners(msg.Signers); err != nil {
+		return err
+	}
 	if len(msg.UUID) == 0 {
-		return sdk.ErrUnknownRequest("UUID cannot be blank")
+		return ErrInvalidTx(DefaultCodespace, "UUID cannot be blank")
 	}
 	if msg.TxID == "" {
-		return sdk.ErrUnknownRequest("Transaction ID cannot be blank")
+		return ErrInvalidTx(DefaultCodespace, "Transaction ID cannot be blank")
+	}
+	// tendermint tx hashes are hex encoded sha256 hashes
+	if bz, err := hex.DecodeString(msg.TxID); err != nil || len(bz) != sha256.Size {
+		return ErrInvalidTx(DefaultCodespace, "Transaction ID must be a hex encoded tx hash")
+	}
+	if msg.Height <= 0 {
+		return ErrInvalidTx(DefaultCodespace, "Transaction height must be positive")
+	}
+	if sdk.CodeType(msg.Code) != sdk.CodeOK {
+		return ErrInvalidTx(DefaultCodespace,
+			fmt.Sprintf("Transaction failed with code %d", msg.Code),
+		)
 	}
 	return nil
 }
@@ -208,3 +428,148 @@ func (msg MsgCompleteTransaction) GetSignBytes() []byte {
 func (msg MsgCompleteTransaction) GetSigners() []sdk.AccAddress {
 	return msg.Signers
 }
+
+// MsgCancelTransaction defines a message to withdraw a transaction request,
+// either by its creator or by a threshold of wallet members
+type MsgCancelTransaction struct {
+	Signers []sdk.AccAddress `json:"signers"`
+	UUID    string           `json:"uuid"`
+}
+
+// NewMsgCancelTransaction is a constructor function for MsgCancelTransaction
+func NewMsgCancelTransaction(uid string, signers []sdk.AccAddress) MsgCancelTransaction {
+	return MsgCancelTransaction{
+		UUID:    uid,
+		Signers: signers,
+	}
+}
+
+// Route should return the name of the module
+func (msg MsgCancelTransaction) Route() string { return RouterKey }
+
+// Type should return the action
+func (msg MsgCancelTransaction) Type() string { return "cancel_transaction" }
+
+// ValidateBasic runs stateless checks on the message
+func (msg MsgCancelTransaction) ValidateBasic() sdk.Error {
+	if err := validateSigners(msg.Signers); err != nil {
+		return err
+	}
+	if len(msg.UUID) == 0 {
+		return ErrInvalidTx(DefaultCodespace, "UUID cannot be blank")
+	}
+	return nil
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgCancelTransaction) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgCancelTransaction) GetSigners() []sdk.AccAddress {
+	return msg.Signers
+}
+
+// MsgRejectTransaction defines a message for wallet members to refuse to sign
+// a transaction request
+type MsgRejectTransaction struct {
+	Signers []sdk.AccAddress `json:"signers"`
+	UUID    string           `json:"uuid"`
+}
+
+// NewMsgRejectTransaction is a constructor function for MsgRejectTransaction
+func NewMsgRejectTransaction(uid string, signers []sdk.AccAddress) MsgRejectTransaction {
+	return MsgRejectTransaction{
+		UUID:    uid,
+		Signers: signers,
+	}
+}
+
+// Route should return the name of the module
+func (msg MsgRejectTransaction) Route() string { return RouterKey }
+
+// Type should return the action
+func (msg MsgRejectTransaction) Type() string { return "reject_transaction" }
+
+// ValidateBasic runs stateless checks on the message
+func (msg MsgRejectTransaction) ValidateBasic() sdk.Error {
+	if err := validateSigners(msg.Signers); err != nil {
+		return err
+	}
+	if len(msg.UUID) == 0 {
+		return ErrInvalidTx(DefaultCodespace, "UUID cannot be blank")
+	}
+	return nil
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgRejectTransaction) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgRejectTransaction) GetSigners() []sdk.AccAddress {
+	return msg.Signers
+}
+
+// MsgProposeWalletUpdate defines a message to propose, or approve, replacing
+// a wallet by a successor wallet with new pubkeys or threshold
+type MsgProposeWalletUpdate struct {
+	Fee      auth.StdFee      `json:"fee"`
+	MinSigTx int              `json:"min_sig_tx"`
+	Name     string           `json:"name"`
+	PubKeys  []string         `json:"pub_keys"`
+	Signers  []sdk.AccAddress `json:"signers"`
+	Wallet   sdk.AccAddress   `json:"wallet"`
+}
+
+// NewMsgProposeWalletUpdate is a constructor function for MsgProposeWalletUpdate.
+// The fee is the fee of the transaction sweeping the balance of the wallet to
+// its successor.
+func NewMsgProposeWalletUpdate(wallet sdk.AccAddress, name string, pubKeys []string, min int, fee auth.StdFee, signers []sdk.AccAddress) MsgProposeWalletUpdate {
+	return MsgProposeWalletUpdate{
+		Wallet:   wallet,
+		Name:     name,
+		PubKeys:  pubKeys,
+		MinSigTx: min,
+		Fee:      fee,
+		Signers:  signers,
+	}
+}
+
+// Route should return the name of the module
+func (msg MsgProposeWalletUpdate) Route() string { return RouterKey }
+
+// Type should return the action
+func (msg MsgProposeWalletUpdate) Type() string { return "propose_wallet_update" }
+
+// ValidateBasic runs stateless checks on the message
+func (msg MsgProposeWalletUpdate) ValidateBasic() sdk.Error {
+	if msg.Wallet.Empty() {
+		return sdk.ErrInvalidAddress(msg.Wallet.String())
+	}
+	if len(msg.PubKeys) < msg.MinSigTx {
+		return ErrInvalidWallet(DefaultCodespace, "Minimum signature transaction number cannot be larger than the number of public keys")
+	}
+	if msg.MinSigTx < 1 {
+		return ErrInvalidWallet(DefaultCodespace, "Must require at least 1 signatures")
+	}
+	if len(msg.Name) == 0 {
+		return ErrInvalidWallet(DefaultCodespace, "Name cannot be empty")
+	}
+	if len(msg.Signers) == 0 {
+		return ErrInvalidSigner(DefaultCodespace, "Signers cannot be empty")
+	}
+	return ValidateRequestFee(msg.Fee)
+}
+
+// GetSignBytes encodes the message for signing
+func (msg MsgProposeWalletUpdate) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ModuleCdc.MustMarshalJSON(msg))
+}
+
+// GetSigners defines whose signature is required
+func (msg MsgProposeWalletUpdate) GetSigners() []sdk.AccAddress {
+	return msg.Signers
+}
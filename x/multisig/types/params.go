@@ -0,0 +1,116 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/params"
+)
+
+// Default parameter values
+const (
+	DefaultParamspace                   = ModuleName
+	DefaultMaxPubKeys             int64 = 20
+	DefaultMaxPendingTransactions int64 = 100
+	// about a week of 6 second blocks
+	DefaultTransactionLifetime int64 = 100800
+)
+
+// Parameter store keys
+var (
+	KeyMaxPubKeys             = []byte("MaxPubKeys")
+	KeyMaxPendingTransactions = []byte("MaxPendingTransactions")
+	KeyTransactionLifetime    = []byte("TransactionLifetime")
+	KeyAllowedDenoms          = []byte("AllowedDenoms")
+)
+
+// ParamKeyTable for multisig module
+func ParamKeyTable() params.KeyTable {
+	return params.NewKeyTable().RegisterParamSet(&Params{})
+}
+
+// Params defines the limits of the multisig module
+type Params struct {
+	MaxPubKeys             int64    `json:"max_pub_keys"`             // maximum number of pubkeys per wallet
+	MaxPendingTransactions int64    `json:"max_pending_transactions"` // maximum number of open transaction requests per wallet
+	TransactionLifetime    int64    `json:"transaction_lifetime"`     // number of blocks after which open transaction requests expire
+	AllowedDenoms          []string `json:"allowed_denoms"`           // denoms transaction requests can send, any denom if empty
+}
+
+// NewParams creates a new Params object
+func NewParams(maxPubKeys, maxPendingTransactions, transactionLifetime int64, allowedDenoms []string) Params {
+	return Params{
+		MaxPubKeys:             maxPubKeys,
+		MaxPendingTransactions: maxPendingTransactions,
+		TransactionLifetime:    transactionLifetime,
+		AllowedDenoms:          allowedDenoms,
+	}
+}
+
+// DefaultParams returns the default multisig parameters
+func DefaultParams() Params {
+	return NewParams(
+		DefaultMaxPubKeys,
+		DefaultMaxPendingTransactions,
+		DefaultTransactionLifetime,
+		[]string{},
+	)
+}
+
+// Validate checks the parameters are within bounds
+func (p Params) Validate() error {
+	if p.MaxPubKeys < 1 {
+		return fmt.Errorf("max pub keys must be positive: %d", p.MaxPubKeys)
+	}
+	if p.MaxPendingTransactions < 1 {
+		return fmt.Errorf("max pending transactions must be positive: %d", p.MaxPendingTransactions)
+	}
+	if p.TransactionLifetime < 1 {
+		return fmt.Errorf("transaction lifetime must be positive: %d", p.TransactionLifetime)
+	}
+	denoms := make(map[string]bool)
+	for _, denom := range p.AllowedDenoms {
+		if coin, err := sdk.ParseCoin("0" + denom); err != nil || coin.Denom != denom {
+			return fmt.Errorf("invalid allowed denom %q", denom)
+		}
+		if denoms[denom] {
+			return fmt.Errorf("duplicate allowed denom %q", denom)
+		}
+		denoms[denom] = true
+	}
+	return nil
+}
+
+// IsAllowedDenom returns whether transaction requests can send the denom
+func (p Params) IsAllowedDenom(denom string) bool {
+	if len(p.AllowedDenoms) == 0 {
+		return true
+	}
+	for _, allowed := range p.AllowedDenoms {
+		if allowed == denom {
+			return true
+		}
+	}
+	return false
+}
+
+func (p Params) String() string {
+	return fmt.Sprintf(`Multisig Params:
+  MaxPubKeys:             %d
+  MaxPendingTransactions: %d
+  TransactionLifetime:    %d
+  AllowedDenoms:          %s`, p.MaxPubKeys,
+		p.MaxPendingTransactions, p.TransactionLifetime,
+		strings.Join(p.AllowedDenoms, ","))
+}
+
+// Implements params.ParamSet
+func (p *Params) ParamSetPairs() params.ParamSetPairs {
+	return params.ParamSetPairs{
+		{Key: KeyMaxPubKeys, Value: &p.MaxPubKeys},
+		{Key: KeyMaxPendingTransactions, Value: &p.MaxPendingTransactions},
+		{Key: KeyTransactionLifetime, Value: &p.TransactionLifetime},
+		{Key: KeyAllowedDenoms, Value: &p.AllowedDenoms},
+	}
+}
@@ -1,6 +1,11 @@
 package types
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
 
 type QueryWallets []MultiSigWallet
 
@@ -13,7 +18,22 @@ func (n QueryWallets) String() string {
 	return strings.Join(wallets[:], "\n")
 }
 
-type QueryTransactions []Transaction
+// QueryTransaction is a transaction request returned by queries, flagged as
+// stale when its sequence no longer matches the account of its wallet
+type QueryTransaction struct {
+	Transaction Transaction `json:"transaction"`
+	Stale       bool        `json:"stale"`
+}
+
+// implement fmt.Stringer
+func (q QueryTransaction) String() string {
+	if q.Stale {
+		return q.Transaction.format(fmt.Sprintf("%s, stale", q.Transaction.Status))
+	}
+	return q.Transaction.String()
+}
+
+type QueryTransactions []QueryTransaction
 
 // implement fmt.Stringer
 func (n QueryTransactions) String() string {
@@ -23,3 +43,124 @@ func (n QueryTransactions) String() string {
 	}
 	return strings.Join(transactions[:], "\n")
 }
+
+type QueryWalletUpdates []WalletUpdate
+
+// implement fmt.Stringer
+func (n QueryWalletUpdates) String() string {
+	updates := make([]string, len(n))
+	for i, update := range n {
+		updates[i] = update.String()
+	}
+	return strings.Join(updates[:], "\n")
+}
+
+// InvariantResult is the outcome of checking a multisig invariant
+type InvariantResult struct {
+	Route  string `json:"route"`
+	Broken bool   `json:"broken"`
+	Error  string `json:"error"`
+}
+
+type QueryInvariants []InvariantResult
+
+// implement fmt.Stringer
+func (n QueryInvariants) String() string {
+	results := make([]string, len(n))
+	for i, result := range n {
+		if result.Broken {
+			results[i] = fmt.Sprintf("%s: broken - %s", result.Route, result.Error)
+		} else {
+			results[i] = fmt.Sprintf("%s: ok", result.Route)
+		}
+	}
+	return strings.Join(results[:], "\n")
+}
+
+// sort orders of query results
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// QueryWalletsParams defines the params for querying the wallets of a pubkey.
+// A limit of zero returns all wallets.
+type QueryWalletsParams struct {
+	Page  int `json:"page"`
+	Limit int `json:"limit"`
+}
+
+func NewQueryWalletsParams(page, limit int) QueryWalletsParams {
+	return QueryWalletsParams{
+		Page:  page,
+		Limit: limit,
+	}
+}
+
+// ValidateBasic checks the params are well formed
+func (p QueryWalletsParams) ValidateBasic() error {
+	return validatePage(p.Page, p.Limit)
+}
+
+// QueryTransactionsParams defines the params for querying the transactions
+// of a wallet. Transactions are sorted by creation height, a limit of zero
+// returns all transactions and a zero height disables that bound.
+type QueryTransactionsParams struct {
+	Page      int            `json:"page"`
+	Limit     int            `json:"limit"`
+	Sort      string         `json:"sort"`
+	Status    string         `json:"status"`
+	MinHeight int64          `json:"min_height"`
+	MaxHeight int64          `json:"max_height"`
+	Recipient sdk.AccAddress `json:"recipient"`
+	Denom     string         `json:"denom"`
+}
+
+func NewQueryTransactionsParams(page, limit int, sort, status string, minHeight, maxHeight int64, recipient sdk.AccAddress, denom string) QueryTransactionsParams {
+	return QueryTransactionsParams{
+		Page:      page,
+		Limit:     limit,
+		Sort:      sort,
+		Status:    status,
+		MinHeight: minHeight,
+		MaxHeight: maxHeight,
+		Recipient: recipient,
+		Denom:     denom,
+	}
+}
+
+// ValidateBasic checks the params are well formed
+func (p QueryTransactionsParams) ValidateBasic() error {
+	if err := validatePage(p.Page, p.Limit); err != nil {
+		return err
+	}
+	switch p.Sort {
+	case "", SortAsc, SortDesc:
+	default:
+		return fmt.Errorf("invalid sort order %q, must be %s or %s", p.Sort, SortAsc, SortDesc)
+	}
+	if p.Status != "" {
+		if _, err := TransactionStatusFromString(p.Status); err != nil {
+			return err
+		}
+	}
+	if p.MinHeight != 0 && p.MaxHeight != 0 && p.MinHeight > p.MaxHeight {
+		return fmt.Errorf("min height %d is greater than max height %d", p.MinHeight, p.MaxHeight)
+	}
+	if p.Denom != "" {
+		if coin, err := sdk.ParseCoin("0" + p.Denom); err != nil || coin.Denom != p.Denom {
+			return fmt.Errorf("invalid denom %q", p.Denom)
+		}
+	}
+	return nil
+}
+
+func validatePage(page, limit int) error {
+	if page < 0 {
+		return fmt.Errorf("page cannot be negative")
+	}
+	if limit < 0 {
+		return fmt.Errorf("limit cannot be negative")
+	}
+	return nil
+}
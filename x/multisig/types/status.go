@@ -0,0 +1,72 @@
+package types
+
+import (
+	"fmt"
+)
+
+// TransactionStatus is the lifecycle state of a transaction request
+type TransactionStatus string
+
+const (
+	// waiting for wallet members to sign
+	StatusPending TransactionStatus = "pending"
+	// enough signatures have been collected to broadcast the transaction
+	StatusReadyToBroadcast TransactionStatus = "ready_to_broadcast"
+	// the multisig transaction has been broadcast
+	StatusBroadcast TransactionStatus = "broadcast"
+	// the multisig transaction has been included in the blockchain
+	StatusCompleted TransactionStatus = "completed"
+	// the transaction request was withdrawn
+	StatusCancelled TransactionStatus = "cancelled"
+	// the transaction request was not completed in time
+	StatusExpired TransactionStatus = "expired"
+	// too many members refused to sign to reach the threshold
+	StatusRejected TransactionStatus = "rejected"
+)
+
+// the statuses a transaction may move to from each status, ready transactions
+// go back to pending when their signatures become stale
+var statusTransitions = map[TransactionStatus][]TransactionStatus{
+	StatusPending:          {StatusReadyToBroadcast, StatusCancelled, StatusExpired, StatusRejected},
+	StatusReadyToBroadcast: {StatusBroadcast, StatusCancelled, StatusExpired, StatusPending},
+	StatusBroadcast:        {StatusCompleted},
+}
+
+// TransactionStatusFromString parses a transaction status
+func TransactionStatusFromString(str string) (TransactionStatus, error) {
+	status := TransactionStatus(str)
+	switch status {
+	case StatusPending, StatusReadyToBroadcast, StatusBroadcast,
+		StatusCompleted, StatusCancelled, StatusExpired, StatusRejected:
+		return status, nil
+	default:
+		return "", fmt.Errorf("invalid transaction status %q", str)
+	}
+}
+
+// CanTransitionTo returns whether a transaction can move from this status to
+// the given one
+func (s TransactionStatus) CanTransitionTo(status TransactionStatus) bool {
+	for _, next := range statusTransitions[s] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinal returns whether no further transition is possible from the status
+func (s TransactionStatus) IsFinal() bool {
+	return len(statusTransitions[s]) == 0
+}
+
+// IsSignable returns whether signatures can still be added to a transaction
+// with this status
+func (s TransactionStatus) IsSignable() bool {
+	return s == StatusPending || s == StatusReadyToBroadcast
+}
+
+// implement fmt.Stringer
+func (s TransactionStatus) String() string {
+	return string(s)
+}
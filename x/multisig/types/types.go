@@ -1,15 +1,20 @@
 package types
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"strings"
 
 	"github.com/cosmos/cosmos-sdk/crypto/keys"
 	sdk "github.com/cosmos/cosmos-sdk/types"
-	"github.com/google/uuid"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
 	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
 	"github.com/tendermint/tendermint/crypto/multisig"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
 )
 
 // MultiSigWallet is a struct that contains all the metadata of a multiple
@@ -19,6 +24,8 @@ type MultiSigWallet struct {
 	MinSigTx int            `json:"min_sig_tx"` // minimum number of signatures for a transaction
 	Address  sdk.AccAddress `json:"address"`    // address of the wallet
 	PubKeys  []string       `json:"pub_keys"`   // pubkeys of regular accounts to be used for signing transactions on this multisig wallet.
+
+	Successor sdk.AccAddress `json:"successor"` // address of the wallet replacing this one after an update
 }
 
 func createAddress(name string) (sdk.AccAddress, error) {
@@ -50,12 +57,9 @@ func NewMultiSigWallet(name string, pubKeys []string, min int) (MultiSigWallet,
 		return MultiSigWallet{}, err
 	}
 
-	cryptoPubKeys := make([]crypto.PubKey, len(pubKeys))
-	for i, _ := range cryptoPubKeys {
-		cryptoPubKeys[i], err = sdk.GetAccPubKeyBech32(pubKeys[i])
-		if err != nil {
-			return MultiSigWallet{}, err
-		}
+	cryptoPubKeys, err := getCryptoPubKeys(pubKeys)
+	if err != nil {
+		return MultiSigWallet{}, err
 	}
 
 	multikey := multisig.NewPubKeyMultisigThreshold(min, cryptoPubKeys)
@@ -69,47 +73,466 @@ func NewMultiSigWallet(name string, pubKeys []string, min int) (MultiSigWallet,
 	}, nil
 }
 
+// decodes a list of bech32 account pubkeys
+func getCryptoPubKeys(pubKeys []string) ([]crypto.PubKey, error) {
+	var err error
+	cryptoPubKeys := make([]crypto.PubKey, len(pubKeys))
+	for i := range cryptoPubKeys {
+		cryptoPubKeys[i], err = sdk.GetAccPubKeyBech32(pubKeys[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return cryptoPubKeys, nil
+}
+
+// PubKey returns the multisig threshold pubkey of the wallet, which signs the
+// transactions sent from the wallet address
+func (w MultiSigWallet) PubKey() (crypto.PubKey, error) {
+	cryptoPubKeys, err := getCryptoPubKeys(w.PubKeys)
+	if err != nil {
+		return nil, err
+	}
+	return multisig.NewPubKeyMultisigThreshold(w.MinSigTx, cryptoPubKeys), nil
+}
+
+// GetMemberPubKey returns the pubkey of the wallet member with the given
+// account address
+func (w MultiSigWallet) GetMemberPubKey(member sdk.AccAddress) (string, bool) {
+	for _, pubkey := range w.PubKeys {
+		addr, err := PubKeyAddress(pubkey)
+		if err == nil && addr.Equals(member) {
+			return pubkey, true
+		}
+	}
+	return "", false
+}
+
+// IsMember returns whether the account address belongs to one of the wallet
+// pubkeys
+func (w MultiSigWallet) IsMember(member sdk.AccAddress) bool {
+	_, ok := w.GetMemberPubKey(member)
+	return ok
+}
+
+// PubKeyAddress returns the account address of a bech32 account pubkey
+func PubKeyAddress(pubkey string) (sdk.AccAddress, error) {
+	pk, err := sdk.GetAccPubKeyBech32(pubkey)
+	if err != nil {
+		return nil, err
+	}
+	return sdk.AccAddress(pk.Address()), nil
+}
+
+// EncodePubKey returns the bech32 and base64 encodings of an account pubkey,
+// as submitted with signatures. The base64 encoding is the value of the
+// amino JSON encoding of the pubkey, as shown by the account info.
+func EncodePubKey(pubkey crypto.PubKey) (string, string, error) {
+	bech32, err := sdk.Bech32ifyAccPub(pubkey)
+	if err != nil {
+		return "", "", err
+	}
+	var bz []byte
+	switch pk := pubkey.(type) {
+	case secp256k1.PubKeySecp256k1:
+		bz = pk[:]
+	case ed25519.PubKeyEd25519:
+		bz = pk[:]
+	default:
+		bz = pubkey.Bytes()
+	}
+	return bech32, base64.StdEncoding.EncodeToString(bz), nil
+}
+
+// Multisign assembles the amino encoded multisignature of the wallet from
+// the given pubkey signatures. Signatures are matched to the wallet pubkeys
+// by their bech32 pubkey, and entries with an empty signature are skipped.
+func (w MultiSigWallet) Multisign(sigs []Signature) ([]byte, error) {
+	cryptoPubKeys, err := getCryptoPubKeys(w.PubKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	multisignature := multisig.NewMultisig(len(cryptoPubKeys))
+	for _, sig := range sigs {
+		if sig.Signature == "" {
+			continue
+		}
+		pubkey, err := sdk.GetAccPubKeyBech32(sig.PubKey)
+		if err != nil {
+			return nil, err
+		}
+		bz, err := decodeSignature(sig.Signature)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature for %s: %s", sig.PubKey, err.Error())
+		}
+		err = multisignature.AddSignatureFromPubKey(bz, pubkey, cryptoPubKeys)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", sig.PubKey, err.Error())
+		}
+	}
+
+	if len(multisignature.Sigs) < w.MinSigTx {
+		return nil, fmt.Errorf("not enough signatures: %d < %d", len(multisignature.Sigs), w.MinSigTx)
+	}
+
+	return multisignature.Marshal(), nil
+}
+
 // implement fmt.Stringer
 func (w MultiSigWallet) String() string {
+	str := fmt.Sprintf(
+		`Wallet: %s (%d of %d): %s`, w.Name, w.MinSigTx, len(w.PubKeys), w.Address,
+	)
+	if !w.Successor.Empty() {
+		str = fmt.Sprintf("%s --> %s", str, w.Successor)
+	}
+	return strings.TrimSpace(str)
+}
+
+// WalletUpdate is a proposal to replace a wallet by a successor wallet with
+// different pubkeys or threshold. Since the address of a wallet is derived
+// from its pubkeys and threshold, the successor has a new address.
+type WalletUpdate struct {
+	Wallet    sdk.AccAddress   `json:"wallet"`     // address of the wallet to update
+	Successor sdk.AccAddress   `json:"successor"`  // address of the successor wallet
+	Name      string           `json:"name"`       // name of the successor wallet
+	MinSigTx  int              `json:"min_sig_tx"` // threshold of the successor wallet
+	PubKeys   []string         `json:"pub_keys"`   // pubkeys of the successor wallet
+	Approvals []sdk.AccAddress `json:"approvals"`  // members of the current wallet who approved the update
+	CreatedAt int64            `json:"created_at"` // block height
+	Fee       auth.StdFee      `json:"fee"`        // fee of the transaction sweeping the balance to the successor
+}
+
+func NewWalletUpdate(wallet sdk.AccAddress, successor MultiSigWallet, fee auth.StdFee, height int64) WalletUpdate {
+	return WalletUpdate{
+		Wallet:    wallet,
+		Successor: successor.Address,
+		Name:      successor.Name,
+		MinSigTx:  successor.MinSigTx,
+		PubKeys:   successor.PubKeys,
+		CreatedAt: height,
+		Fee:       fee,
+	}
+}
+
+// AddApproval records a member approval of the update
+func (u *WalletUpdate) AddApproval(member sdk.AccAddress) {
+	if !containsAddress(u.Approvals, member) {
+		u.Approvals = append(u.Approvals, member)
+	}
+}
+
+// implement fmt.Stringer
+func (u WalletUpdate) String() string {
 	return strings.TrimSpace(
 		fmt.Sprintf(
-			`Wallet: %s (%d of %d): %s`, w.Name, w.MinSigTx, len(w.PubKeys), w.Address,
+			`Wallet update: %s --> %s: %s (%d of %d), %d approvals`,
+			u.Wallet, u.Successor, u.Name, u.MinSigTx, len(u.PubKeys), len(u.Approvals),
 		),
 	)
 }
 
+// DefaultGas is the gas limit of the multisig transaction built from a
+// transaction request
+const DefaultGas = 200000
+
 type Signature struct {
 	PubKey       string `json:"pub_key"`
 	PubKeyBase64 string `json:"pub_key_base64"`
 	Signature    string `json:"signature"`
 }
 
+// decodes a base64 signature, signatures may or may not have their padding
+func decodeSignature(sig string) ([]byte, error) {
+	return base64.RawStdEncoding.DecodeString(strings.TrimRight(sig, "="))
+}
+
+// Verify checks the signature was made by its pubkey over the given sign bytes
+func (s Signature) Verify(signBytes []byte) error {
+	pubkey, err := sdk.GetAccPubKeyBech32(s.PubKey)
+	if err != nil {
+		return err
+	}
+	bz, err := decodeSignature(s.Signature)
+	if err != nil {
+		return err
+	}
+	if !pubkey.VerifyBytes(signBytes, bz) {
+		return fmt.Errorf("signature does not match pubkey %s", s.PubKey)
+	}
+	return nil
+}
+
 type Transaction struct {
-	UUID       string         `json:"uuid"`
-	From       sdk.AccAddress `json:"from_address"`
-	To         sdk.AccAddress `json:"to_address"`
-	Coins      sdk.Coins      `json:"coins"`
-	Signatures []Signature    `json:"signatures"` // pubkey signatures
-	TxID       string         `json:"tx_id"`      // tx hash given by cosmos once transaction is completed
-	CreatedAt  int64          `json:"created_at"` // block height
+	UUID    string         `json:"uuid"`
+	From    sdk.AccAddress `json:"from_address"`
+	To      sdk.AccAddress `json:"to_address"`
+	Coins   sdk.Coins      `json:"coins"`   // total of the outputs for multisend transactions
+	Outputs []bank.Output  `json:"outputs"` // recipients of multisend transactions, To is then empty
+	// account number and sequence of the wallet the transaction is signed for
+	AccountNumber uint64 `json:"account_number"`
+	Sequence      uint64 `json:"sequence"`
+
+	Memo       string            `json:"memo"`
+	Fee        auth.StdFee       `json:"fee"`
+	Msgs       []sdk.Msg         `json:"msgs"`        // msgs of other modules, To and Coins are then empty
+	Signatures []Signature       `json:"signatures"`  // pubkey signatures
+	TxID       string            `json:"tx_id"`       // tx hash given by cosmos once transaction is completed
+	TxHeight   int64             `json:"tx_height"`   // height of the completed tx
+	TxGasUsed  int64             `json:"tx_gas_used"` // gas used by the completed tx
+	TxCode     uint32            `json:"tx_code"`     // result code of the completed tx
+	CreatedAt  int64             `json:"created_at"`  // block height
+	Status     TransactionStatus `json:"status"`
+
+	Creator       sdk.AccAddress   `json:"creator"`       // account that created the transaction request
+	Cancellations []sdk.AccAddress `json:"cancellations"` // members who voted to cancel
+	Rejections    []sdk.AccAddress `json:"rejections"`    // members who refuse to sign
 }
 
-func NewTransaction(from, to sdk.AccAddress, coins sdk.Coins, height int64, signatures []Signature) Transaction {
+func NewTransaction(uid string, creator, from, to sdk.AccAddress, coins sdk.Coins, height int64, signatures []Signature) Transaction {
 	return Transaction{
-		UUID:       uuid.New().String(),
+		UUID:       uid,
+		Creator:    creator,
 		From:       from,
 		To:         to,
 		Coins:      coins,
 		CreatedAt:  height,
 		Signatures: signatures,
+		Status:     StatusPending,
 	}
 }
 
+// NewMultiSendTransaction creates a transaction request sending coins to
+// several recipients
+func NewMultiSendTransaction(uid string, creator, from sdk.AccAddress, outputs []bank.Output, height int64, signatures []Signature) Transaction {
+	transaction := NewTransaction(uid, creator, from, nil, SumOutputs(outputs), height, signatures)
+	transaction.Outputs = outputs
+	return transaction
+}
+
+// NewMsgsTransaction creates a transaction request for msgs of other modules
+func NewMsgsTransaction(uid string, creator, from sdk.AccAddress, msgs []sdk.Msg, height int64, signatures []Signature) Transaction {
+	transaction := NewTransaction(uid, creator, from, nil, sdk.Coins{}, height, signatures)
+	transaction.Msgs = msgs
+	return transaction
+}
+
+// SumOutputs returns the total of coins sent to a list of outputs
+func SumOutputs(outputs []bank.Output) sdk.Coins {
+	total := sdk.NewCoins()
+	for _, output := range outputs {
+		total = total.Add(output.Coins)
+	}
+	return total
+}
+
+// SumMsgsCoins returns the total of coins sent by the bank msgs of a list of
+// msgs
+func SumMsgsCoins(msgs []sdk.Msg) sdk.Coins {
+	total := sdk.NewCoins()
+	for _, m := range msgs {
+		switch m := m.(type) {
+		case bank.MsgSend:
+			total = total.Add(m.Amount)
+		case bank.MsgMultiSend:
+			for _, input := range m.Inputs {
+				total = total.Add(input.Coins)
+			}
+		}
+	}
+	return total
+}
+
+// IsMultiSend returns whether the transaction sends coins to several
+// recipients
+func (t Transaction) IsMultiSend() bool {
+	return len(t.Outputs) > 0
+}
+
+// HasMsgs returns whether the transaction holds msgs of other modules
+func (t Transaction) HasMsgs() bool {
+	return len(t.Msgs) > 0
+}
+
+// Recipients returns the addresses the transaction sends coins to
+func (t Transaction) Recipients() []sdk.AccAddress {
+	if !t.IsMultiSend() {
+		if t.To.Empty() {
+			return nil
+		}
+		return []sdk.AccAddress{t.To}
+	}
+	var recipients []sdk.AccAddress
+	for _, output := range t.Outputs {
+		if !containsAddress(recipients, output.Address) {
+			recipients = append(recipients, output.Address)
+		}
+	}
+	return recipients
+}
+
+// HasRecipient returns whether the transaction sends coins to the address
+func (t Transaction) HasRecipient(address sdk.AccAddress) bool {
+	return containsAddress(t.Recipients(), address)
+}
+
+// GetMsgs returns the messages of the multisig transaction
+func (t Transaction) GetMsgs() []sdk.Msg {
+	if t.HasMsgs() {
+		return t.Msgs
+	}
+	if t.IsMultiSend() {
+		return []sdk.Msg{
+			bank.MsgMultiSend{
+				Inputs:  []bank.Input{bank.NewInput(t.From, t.Coins)},
+				Outputs: t.Outputs,
+			},
+		}
+	}
+	return []sdk.Msg{
+		bank.MsgSend{
+			FromAddress: t.From,
+			ToAddress:   t.To,
+			Amount:      t.Coins,
+		},
+	}
+}
+
+// GetFee returns the fee of the multisig transaction
+func (t Transaction) GetFee() auth.StdFee {
+	return t.Fee
+}
+
+// GetStdTx returns the unsigned multisig transaction
+func (t Transaction) GetStdTx() auth.StdTx {
+	return auth.NewStdTx(t.GetMsgs(), t.GetFee(), nil, t.Memo)
+}
+
+// StdSignBytes returns the bytes every member of the wallet must sign for the
+// multisig transaction
+func (t Transaction) StdSignBytes(chainID string, accnum, sequence uint64) []byte {
+	return auth.StdSignBytes(chainID, accnum, sequence, t.GetFee(), t.GetMsgs(), t.Memo)
+}
+
+// GetSignBytes returns the bytes every member of the wallet must sign for the
+// multisig transaction, with the pinned account number and sequence
+func (t Transaction) GetSignBytes(chainID string) []byte {
+	return t.StdSignBytes(chainID, t.AccountNumber, t.Sequence)
+}
+
+// IsStale returns whether the transaction was pinned to another account
+// number or sequence than the given ones, signatures of stale transactions
+// can no longer be broadcast
+func (t Transaction) IsStale(accnum, sequence uint64) bool {
+	return t.AccountNumber != accnum || t.Sequence != sequence
+}
+
+// Repin pins the transaction to a new account number and sequence, removing
+// the signatures made for the previous ones. Ready transactions go back to
+// pending.
+func (t *Transaction) Repin(accnum, sequence uint64) {
+	t.AccountNumber = accnum
+	t.Sequence = sequence
+	for i := range t.Signatures {
+		t.Signatures[i].Signature = ""
+		t.Signatures[i].PubKeyBase64 = ""
+	}
+	if t.Status == StatusReadyToBroadcast {
+		t.Status = StatusPending
+	}
+}
+
+// VerifyTxResponse checks a tx looked up by hash is the successful broadcast
+// of the transaction: its msgs, fee and memo must match
+func (t Transaction) VerifyTxResponse(res sdk.TxResponse) error {
+	if sdk.CodeType(res.Code) != sdk.CodeOK {
+		return fmt.Errorf("tx %s failed with code %d: %s", res.TxHash, res.Code, res.RawLog)
+	}
+	stdTx, ok := res.Tx.(auth.StdTx)
+	if !ok {
+		return fmt.Errorf("tx %s is not a standard tx", res.TxHash)
+	}
+	msgs := t.GetMsgs()
+	if len(stdTx.Msgs) != len(msgs) {
+		return fmt.Errorf("tx %s has %d msgs, the transaction has %d", res.TxHash, len(stdTx.Msgs), len(msgs))
+	}
+	for i, msg := range msgs {
+		if !bytes.Equal(stdTx.Msgs[i].GetSignBytes(), msg.GetSignBytes()) {
+			return fmt.Errorf("msg %d of tx %s does not match the transaction", i, res.TxHash)
+		}
+	}
+	if stdTx.Memo != t.Memo {
+		return fmt.Errorf("memo of tx %s does not match the transaction", res.TxHash)
+	}
+	if !bytes.Equal(stdTx.Fee.Bytes(), t.GetFee().Bytes()) {
+		return fmt.Errorf("fee of tx %s does not match the transaction", res.TxHash)
+	}
+	return nil
+}
+
+// CountSignatures returns the number of signatures added to the transaction
+func (t Transaction) CountSignatures() int {
+	count := 0
+	for _, sig := range t.Signatures {
+		if sig.Signature != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// SetStatus moves the transaction to a new status, enforcing the allowed
+// status transitions
+func (t *Transaction) SetStatus(status TransactionStatus) error {
+	if !t.Status.CanTransitionTo(status) {
+		return fmt.Errorf("cannot move transaction from %s to %s", t.Status, status)
+	}
+	t.Status = status
+	return nil
+}
+
+// HasSigned returns whether a signature was added for the pubkey
+func (t Transaction) HasSigned(pubkey string) bool {
+	for _, sig := range t.Signatures {
+		if sig.PubKey == pubkey {
+			return sig.Signature != ""
+		}
+	}
+	return false
+}
+
+// AddCancellation records a member vote to cancel the transaction
+func (t *Transaction) AddCancellation(member sdk.AccAddress) {
+	if !containsAddress(t.Cancellations, member) {
+		t.Cancellations = append(t.Cancellations, member)
+	}
+}
+
+// AddRejection records a member refusing to sign the transaction
+func (t *Transaction) AddRejection(member sdk.AccAddress) {
+	if !containsAddress(t.Rejections, member) {
+		t.Rejections = append(t.Rejections, member)
+	}
+}
+
+// HasRejected returns whether a member refused to sign the transaction
+func (t Transaction) HasRejected(member sdk.AccAddress) bool {
+	return containsAddress(t.Rejections, member)
+}
+
+func containsAddress(addrs []sdk.AccAddress, addr sdk.AccAddress) bool {
+	for _, a := range addrs {
+		if a.Equals(addr) {
+			return true
+		}
+	}
+	return false
+}
+
 // adds a signature to Transaction. If signature already exists, overwrite
 func (t *Transaction) AddSignature(pubkey, pubkey_base64, signature string) error {
 	for i, sig := range t.Signatures {
-		fmt.Println(pubkey)
-		fmt.Println(sig.PubKey)
 		if sig.PubKey == pubkey {
 			t.Signatures[i].Signature = signature
 			t.Signatures[i].PubKeyBase64 = pubkey_base64
@@ -120,9 +543,39 @@ func (t *Transaction) AddSignature(pubkey, pubkey_base64, signature string) erro
 }
 
 func (t Transaction) String() string {
+	return t.format(t.Status.String())
+}
+
+// formats the transaction with the given status label
+func (t Transaction) format(status string) string {
+	if t.HasMsgs() {
+		msgs := make([]string, len(t.Msgs))
+		for i, m := range t.Msgs {
+			msgs[i] = fmt.Sprintf("  %s/%s %s", m.Route(), m.Type(), m.GetSignBytes())
+		}
+		return strings.TrimSpace(
+			fmt.Sprintf(
+				"Transaction (%s) [%s]: %s (memo: %q, fee: %s, gas: %d)\n%s",
+				t.UUID, status, t.From, t.Memo, t.Fee.Amount, t.Fee.Gas, strings.Join(msgs, "\n"),
+			),
+		)
+	}
+	if t.IsMultiSend() {
+		outputs := make([]string, len(t.Outputs))
+		for i, output := range t.Outputs {
+			outputs[i] = fmt.Sprintf("  --> %s %+v", output.Address, output.Coins)
+		}
+		return strings.TrimSpace(
+			fmt.Sprintf(
+				"Transaction (%s) [%s]: %s %+v (memo: %q, fee: %s, gas: %d)\n%s",
+				t.UUID, status, t.From, t.Coins, t.Memo, t.Fee.Amount, t.Fee.Gas, strings.Join(outputs, "\n"),
+			),
+		)
+	}
 	return strings.TrimSpace(
 		fmt.Sprintf(
-			`Transaction (%s): %s --> %s %+v`, t.UUID, t.From, t.To, t.Coins,
+			`Transaction (%s) [%s]: %s --> %s %+v (memo: %q, fee: %s, gas: %d)`,
+			t.UUID, status, t.From, t.To, t.Coins, t.Memo, t.Fee.Amount, t.Fee.Gas,
 		),
 	)
 }